@@ -4,11 +4,67 @@ Claude Code Project Generator
 Scaffold new projects from templates.
 """
 
+import argparse
+import json
 import os
+import re
 import sys
 import shutil
 from pathlib import Path
 
+# Legacy bidder param spellings accepted by every generated prebid adapter
+DEFAULT_PARAM_ALIASES = {"placement_id": "placementId"}
+
+# Import prefix of upstream Prebid Server; forks vendor it under other paths
+DEFAULT_MODULE_PATH = "github.com/prebid/prebid-server/v2"
+
+# openrtb major version the template is written against
+DEFAULT_OPENRTB_VERSION = 20
+
+# openrtb major versions the rendered adapter is built and tested against;
+# add one only after the template builds with it
+VERIFIED_OPENRTB_VERSIONS = (20,)
+
+# Go types accepted by --config-field
+CONFIG_FIELD_TYPES = ("string", "bool", "int", "int64", "float64", "[]string", "map[string]string")
+
+# Test scaffolds selectable with --test-style; the first is the default
+TEST_STYLES = ("json", "table")
+
+# Package shared by every generated prebid adapter (--with-helpers), kept under
+# templates/_shared and emitted once into the working directory
+SHARED_HELPERS = "lughhelpers"
+
+# Template files only emitted when the named option is set, when it is not
+# set for "!option" entries, or set to the given value for (option, value) entries
+OPTIONAL_FILES = {
+    "prebid-adapter": {
+        "alias.go": "alias_builder",
+        "alias_test.go": "alias_builder",
+        "config.go": "config_fields",
+        "config_test.go": "config_fields",
+        "config_empty.go": "!config_fields",
+        "usersync/usersync.go": "with_usersync",
+        "usersync/usersync_test.go": "with_usersync",
+        "cmd/replay/main.go": "with_replay",
+        "{{NAME_LOWER}}/exemplary/test-mode.json": "test_mode",
+        "helpers.go": "with_helpers",
+        "helpers_local.go": "!with_helpers",
+        "java/src/main/resources/bidder-config/{{NAME_LOWER}}.yaml": "with_java",
+        "adapter_json_test.go": ("test_style", "json"),
+        "adapter_table_test.go": ("test_style", "table"),
+    },
+}
+
+
+def file_enabled(option, options: dict) -> bool:
+    """Report whether an OPTIONAL_FILES entry is emitted with these options."""
+    if isinstance(option, tuple):
+        return options.get(option[0]) == option[1]
+    if option and option.startswith("!"):
+        return not options.get(option[1:])
+    return not option or bool(options.get(option))
+
 
 def get_templates_dir():
     return Path.home() / ".claude" / "templates"
@@ -20,7 +76,8 @@ def list_templates():
         print("No templates directory found.")
         return []
     
-    templates = [d.name for d in templates_dir.iterdir() if d.is_dir()]
+    # Directories starting with _ hold shared code, not templates
+    templates = [d.name for d in templates_dir.iterdir() if d.is_dir() and not d.name.startswith("_")]
     return sorted(templates)
 
 
@@ -30,7 +87,24 @@ def show_help():
     print("=" * 50)
     print()
     print("Usage:")
-    print("  claude-new <template> <name> [description]")
+    print("  claude-new <template> <name> [description] [options]")
+    print()
+    print("  claude-new check <adapter-dir>   Report drift from the prebid-adapter template")
+    print()
+    print("Options (prebid-adapter):")
+    print("  --param-alias LEGACY=CANONICAL   Accept a legacy bidder param spelling")
+    print(f"  --module-path PATH               PBS import prefix (default {DEFAULT_MODULE_PATH})")
+    verified = ", ".join(str(version) for version in VERIFIED_OPENRTB_VERSIONS)
+    print(f"  --openrtb-version N              openrtb major version to import (default {DEFAULT_OPENRTB_VERSION}, verified: {verified})")
+    print("  --alias-builder                  Add an AliasBuilder for registering aliases")
+    print("  --with-usersync                  Add a Go usersync stub package")
+    print("  --with-replay                    Add cmd/replay to print the requests for a saved BidRequest")
+    print("  --config-field NAME:TYPE         Add an adapterConfig field, read from extra_info's config object")
+    print("  --test-style json|table          JSON-sample test (default) or table-driven tests")
+    print("  --test-mode                      Add an exemplary test=1 sample for sandbox routing")
+    print("  --with-java                      Also emit prebid-server-java bidder-config yaml and params schema")
+    print("  --with-helpers                   Use the shared lughhelpers package, emitted once beside the adapters")
+    print("  --license FILE                   Put FILE's text as a comment header atop each Go file")
     print()
     print("Templates:")
     for t in list_templates():
@@ -58,11 +132,189 @@ def replace_placeholders(content: str, replacements: dict) -> str:
     return content
 
 
-def generate_project(template: str, name: str, description: str = None):
+def parse_param_aliases(pairs: list) -> dict:
+    """Parse LEGACY=CANONICAL pairs on top of the default aliases."""
+    aliases = dict(DEFAULT_PARAM_ALIASES)
+    for pair in pairs or []:
+        legacy, sep, canonical = pair.partition("=")
+        if not sep or not legacy or not canonical:
+            raise ValueError(f"Invalid --param-alias '{pair}', expected LEGACY=CANONICAL")
+        aliases[legacy] = canonical
+    return aliases
+
+
+def schema_required_groups(schema: dict) -> dict:
+    """Map each param the schema requires to the set of names satisfying it.
+
+    A param required through an anyOf (itself or a legacy spelling) maps to
+    every name in that anyOf; anyOfs may also sit inside a top-level allOf.
+    """
+    groups = {name: {name} for name in schema.get("required", [])}
+    any_ofs = [schema.get("anyOf", [])] + [item.get("anyOf", []) for item in schema.get("allOf", [])]
+    for any_of in any_ofs:
+        names = {name for branch in any_of for name in branch.get("required", [])}
+        for name in names:
+            groups[name] = names
+    return groups
+
+
+def alias_schema(schema: dict, aliases: dict) -> dict:
+    """Declare every legacy spelling in the params schema.
+
+    PBS validates imp params against the schema before the adapter's
+    UnmarshalJSON runs, so each legacy spelling needs a property like its
+    canonical param, and a required param becomes an anyOf of its spellings.
+    """
+    legacy_names = {}
+    for legacy, canonical in sorted(aliases.items()):
+        legacy_names.setdefault(canonical, []).append(legacy)
+    required = schema_required_groups(schema)
+
+    properties = {}
+    for name, prop in schema["properties"].items():
+        if name in aliases:
+            continue
+        properties[name] = prop
+        for legacy in legacy_names.get(name, []):
+            properties[legacy] = {**{k: v for k, v in prop.items() if k != "description"}, "description": f"Legacy spelling of {name}"}
+
+    plain, alternatives = [], []
+    for name in schema["properties"]:
+        if name not in required or name in aliases:
+            continue
+        if name in legacy_names:
+            alternatives.append([{"required": [spelling]} for spelling in [name] + legacy_names[name]])
+        else:
+            plain.append(name)
+
+    aliased = {k: v for k, v in schema.items() if k not in ("properties", "required", "anyOf", "allOf")}
+    aliased["properties"] = properties
+    if plain:
+        aliased["required"] = plain
+    if len(alternatives) == 1:
+        aliased["anyOf"] = alternatives[0]
+    elif alternatives:
+        aliased["allOf"] = [{"anyOf": any_of} for any_of in alternatives]
+    return aliased
+
+
+def param_defaults(pairs: list, schema: dict) -> str:
+    """Render NAME=VALUE pairs as a Go string holding params JSON.
+
+    Values are typed from the params schema; array params take a
+    comma-separated list. Required params are refused since the core rejects
+    imps without them before the adapter could apply a default.
+    """
+    properties = schema.get("properties", {})
+    required = schema_required_groups(schema)
+    defaults = {}
+    for pair in pairs or []:
+        key, sep, value = pair.partition("=")
+        if not sep or key not in properties:
+            raise ValueError(f"Invalid --param-default '{pair}', expected NAME=VALUE with NAME one of {', '.join(sorted(properties))}")
+        if key in required:
+            raise ValueError(f"Invalid --param-default '{pair}', {key} is required by the params schema")
+        kind = properties[key].get("type")
+        try:
+            if kind == "integer":
+                defaults[key] = int(value)
+            elif kind == "number":
+                defaults[key] = float(value)
+            elif kind == "boolean":
+                defaults[key] = {"true": True, "false": False}[value]
+            elif kind == "array":
+                defaults[key] = [item for item in value.split(",") if item]
+            else:
+                defaults[key] = value
+        except (KeyError, ValueError):
+            raise ValueError(f"Invalid --param-default '{pair}', {key} expects a {kind}")
+    if not defaults:
+        return '""'
+    return json.dumps(json.dumps(defaults, sort_keys=True, separators=(",", ":")))
+
+
+def config_fields(specs: list) -> str:
+    """Render NAME:TYPE specs as gofmt-aligned adapterConfig struct fields."""
+    fields = []
+    for spec in specs or []:
+        key, sep, go_type = spec.partition(":")
+        if not sep or not re.fullmatch(r"[A-Za-z][A-Za-z0-9_]*", key) or go_type not in CONFIG_FIELD_TYPES:
+            raise ValueError(f"Invalid --config-field '{spec}', expected NAME:TYPE with TYPE one of {', '.join(CONFIG_FIELD_TYPES)}")
+        field = "".join(part[:1].upper() + part[1:] for part in key.split("_"))
+        fields.append((field, go_type, f'`json:"{key}"`'))
+    if not fields:
+        return ""
+    name_width = max(len(f) for f, _, _ in fields)
+    type_width = max(len(t) for _, t, _ in fields)
+    return "\n\t".join(f"{f:<{name_width}} {t:<{type_width}} {tag}" for f, t, tag in fields)
+
+
+def license_header(text: str) -> str:
+    """Render license text as a Go line-comment block.
+
+    The trailing blank line keeps the header off the package doc comment, and
+    since it is only line comments a //go:build constraint may still follow it.
+    """
+    if not text or not text.strip():
+        return ""
+    lines = [line.rstrip() for line in text.strip("\n").splitlines()]
+    comment = [line if line.startswith("//") else f"// {line}".rstrip() for line in lines]
+    return "\n".join(comment) + "\n\n"
+
+
+def prebid_replacements(options: dict) -> dict:
+    """Build the placeholders only the prebid-adapter template uses."""
+    aliases = parse_param_aliases(options.get("param_aliases"))
+    # Pad keys the way gofmt aligns map literal values
+    width = max(len(legacy) for legacy in aliases) + 3
+    entries = []
+    for legacy, canonical in sorted(aliases.items()):
+        key = f'"{legacy}":'
+        entries.append(f'{key:<{width}} "{canonical}",')
+    module_path = (options.get("module_path") or DEFAULT_MODULE_PATH).rstrip("/")
+    if not module_path or " " in module_path:
+        raise ValueError(f"Invalid --module-path '{options.get('module_path')}'")
+    openrtb_version = options.get("openrtb_version") or DEFAULT_OPENRTB_VERSION
+    if str(openrtb_version) not in [str(version) for version in VERIFIED_OPENRTB_VERSIONS]:
+        verified = ", ".join(str(version) for version in VERIFIED_OPENRTB_VERSIONS)
+        raise ValueError(f"Invalid --openrtb-version '{openrtb_version}', the template is verified against {verified}")
+    schema = json.loads((get_templates_dir() / "prebid-adapter" / "static" / "bidder-params" / "{{NAME_LOWER}}.json").read_text())
+    for legacy, canonical in sorted(aliases.items()):
+        if canonical not in schema["properties"] or canonical in aliases:
+            raise ValueError(f"Invalid --param-alias '{legacy}={canonical}', {canonical} is not a bidder param")
+        if legacy in schema["properties"] and legacy not in DEFAULT_PARAM_ALIASES:
+            raise ValueError(f"Invalid --param-alias '{legacy}={canonical}', {legacy} is already a bidder param")
+    return {
+        "PARAM_ALIASES": "\n\t".join(entries),
+        "PARAM_DEFAULTS": param_defaults(options.get("param_defaults"), schema),
+        "OPENRTB_MODULE": f"github.com/prebid/openrtb/v{int(openrtb_version)}",
+        "PBS_MODULE": module_path,
+        "CONFIG_FIELDS": config_fields(options.get("config_fields")),
+    }
+
+
+def emit_shared_helpers(templates_dir: Path, replacements: dict, header: str):
+    """Write the shared helpers package into the working directory, once.
+
+    Adapters generated later in the same directory reuse the existing copy so
+    they all import identical code.
+    """
+    target_dir = Path.cwd() / SHARED_HELPERS
+    if target_dir.exists():
+        print(f"  ✓ {SHARED_HELPERS}/ already present, reusing it")
+        return
+    target_dir.mkdir()
+    for source_file in sorted((templates_dir / "_shared" / SHARED_HELPERS).glob("*.go")):
+        content = header + replace_placeholders(source_file.read_text(), replacements)
+        (target_dir / source_file.name).write_text(content)
+        print(f"  ✓ ../{SHARED_HELPERS}/{source_file.name}")
+
+
+def generate_project(template: str, name: str, description: str = None, options: dict = None):
     templates_dir = get_templates_dir()
     template_dir = templates_dir / template
     
-    if not template_dir.exists():
+    if template.startswith("_") or not template_dir.exists():
         print(f"❌ Template '{template}' not found")
         print(f"Available: {', '.join(list_templates())}")
         return False
@@ -74,6 +326,23 @@ def generate_project(template: str, name: str, description: str = None):
         print(f"❌ Directory '{name}' already exists")
         return False
     
+    options = dict(options or {})
+    if template == "prebid-adapter":
+        options["test_style"] = options.get("test_style") or TEST_STYLES[0]
+        if options["test_style"] not in TEST_STYLES:
+            print(f"❌ Invalid --test-style '{options['test_style']}', expected one of {', '.join(TEST_STYLES)}")
+            return False
+        if options.get("test_mode") and options["test_style"] != "json":
+            print("❌ --test-mode adds a JSON sample, which needs --test-style json")
+            return False
+    try:
+        template_replacements = prebid_replacements(options) if template == "prebid-adapter" else {}
+    except ValueError as e:
+        print(f"❌ {e}")
+        return False
+
+    header = license_header(options.get("license"))
+
     # Default description
     if not description:
         description = f"{name} - generated from {template} template"
@@ -84,6 +353,7 @@ def generate_project(template: str, name: str, description: str = None):
         "NAME_LOWER": name.lower().replace("-", "_"),
         "NAME_UPPER": name.upper().replace("-", "_"),
         "DESCRIPTION": description,
+        **template_replacements,
     }
     
     # Copy template
@@ -92,24 +362,27 @@ def generate_project(template: str, name: str, description: str = None):
     output_dir.mkdir(parents=True)
     
     for root, dirs, files in os.walk(template_dir):
-        # Get relative path
+        # Get relative path (placeholders are allowed in names too)
         rel_root = Path(root).relative_to(template_dir)
-        target_dir = output_dir / rel_root
-        
-        # Create directories
-        for d in dirs:
-            (target_dir / d).mkdir(exist_ok=True)
+        target_dir = output_dir / replace_placeholders(str(rel_root), replacements)
         
         # Copy and process files
+        optional = OPTIONAL_FILES.get(template, {})
         for f in files:
+            if not file_enabled(optional.get(str(rel_root / f)), options):
+                continue
             source_file = Path(root) / f
-            target_file = target_dir / f
+            target_file = target_dir / replace_placeholders(f, replacements)
+            # Directories are created on demand so skipped optional files leave none behind
+            target_file.parent.mkdir(parents=True, exist_ok=True)
             
             # Read content
             try:
                 content = source_file.read_text()
                 # Replace placeholders
                 content = replace_placeholders(content, replacements)
+                if target_file.suffix == ".go":
+                    content = header + content
                 # Write
                 target_file.write_text(content)
                 print(f"  ✓ {target_file.relative_to(output_dir)}")
@@ -118,6 +391,25 @@ def generate_project(template: str, name: str, description: str = None):
                 shutil.copy2(source_file, target_file)
                 print(f"  ✓ {target_file.relative_to(output_dir)} (binary)")
     
+    if template == "prebid-adapter":
+        # The template schema declares the default aliases; declare the rest
+        aliases = parse_param_aliases(options.get("param_aliases"))
+        if aliases != DEFAULT_PARAM_ALIASES:
+            schema_path = output_dir / "static" / "bidder-params" / f"{replacements['NAME_LOWER']}.json"
+            schema = alias_schema(json.loads(schema_path.read_text()), aliases)
+            schema_path.write_text(json.dumps(schema, indent=2) + "\n")
+
+    if template == "prebid-adapter" and options.get("with_java"):
+        # prebid-server-java validates the same params schema, so share the file
+        schema = Path("static") / "bidder-params" / f"{replacements['NAME_LOWER']}.json"
+        java_schema = Path("java") / "src" / "main" / "resources" / schema
+        (output_dir / java_schema).parent.mkdir(parents=True, exist_ok=True)
+        shutil.copy2(output_dir / schema, output_dir / java_schema)
+        print(f"  ✓ {java_schema}")
+
+    if template == "prebid-adapter" and options.get("with_helpers"):
+        emit_shared_helpers(templates_dir, replacements, header)
+
     # Create CLAUDE.md for context
     claude_md = f"""# {name} - Claude Code Context
 
@@ -166,13 +458,158 @@ Check the README.md for setup instructions.
         print("  python src/main.py")
     elif template == "prebid-adapter":
         print("  # Copy files to your PBS adapters directory")
+        print("  # Copy static/bidder-params/ and static/bidder-info/ into the PBS static/ directory")
         print("  # Register adapter in exchange/adapter_builders.go")
+        if options.get("with_java"):
+            print("  # Copy java/src/main/resources/ into prebid-server-java and add the Java bidder")
+        if options.get("with_helpers"):
+            print(f"  # Copy ../{SHARED_HELPERS}/ to adapters/internal/{SHARED_HELPERS}/ once for all adapters")
     elif template == "n8n-workflow":
         print("  # Import workflow.json into n8n")
     
     return True
 
 
+# The funcs every adapter needs: adapters.Bidder and the Builder the core
+# registers. Anything else the template exports is optional, so an adapter
+# generated from an older template is not drift.
+REQUIRED_FUNCTIONS = ("Builder", "MakeRequests", "MakeBids")
+
+
+def func_signatures(code: str) -> dict:
+    """Map exported func and method names to their parameter and result types."""
+    signatures = {}
+    for name, params, results in re.findall(r"^func (?:\([^)]*\) )?([A-Z]\w*)\(([^)]*)\) ?(.*?) ?\{$", code, re.M):
+        results = results.strip()
+        if results.startswith("("):
+            results = f"({signature_types(results[1:-1])})"
+        signatures[name] = f"({signature_types(params)}) {results}".rstrip()
+    return signatures
+
+
+def signature_types(fields: str) -> str:
+    """Drop the names from a Go parameter list, keeping a type per parameter."""
+    pieces = [piece.split() for piece in fields.split(",") if piece.strip()]
+    if not any(len(piece) > 1 for piece in pieces):
+        return ", ".join(" ".join(piece) for piece in pieces)
+    types, current = [], ""
+    for piece in reversed(pieces):
+        # a lone name shares the type of the parameter after it
+        current = " ".join(piece[1:]) if len(piece) > 1 else current
+        types.append(current)
+    return ", ".join(reversed(types))
+
+
+def template_signatures(template_dir: Path) -> dict:
+    """Signatures of REQUIRED_FUNCTIONS as the template defines them."""
+    optional = OPTIONAL_FILES.get(template_dir.name, {})
+    signatures = {}
+    for go_file in template_dir.rglob("*.go"):
+        rel = str(go_file.relative_to(template_dir))
+        if rel.endswith("_test.go") or rel in optional:
+            continue
+        signatures.update(func_signatures(go_file.read_text()))
+    return {name: signatures[name] for name in REQUIRED_FUNCTIONS if name in signatures}
+
+
+def params_tags(source: str) -> dict:
+    """Map the json tags of the ExtImp struct to whether they are required."""
+    struct = re.search(r"^type ExtImp\w* struct \{\n(.*?)^\}", source, re.M | re.S)
+    if not struct:
+        return {}
+    tags = re.findall(r'`json:"([^",]+)(,omitempty)?"`', struct.group(1))
+    return {name: not omitempty for name, omitempty in tags}
+
+
+def params_aliases(source: str) -> dict:
+    """Map the legacy spellings in the extImp aliases map to their canonical tag."""
+    block = re.search(r"^var extImp\w*Aliases = map\[string\]string\{\n(.*?)^\}", source, re.M | re.S)
+    if not block:
+        return {}
+    return dict(re.findall(r'"([^"]+)":\s*"([^"]+)"', block.group(1)))
+
+
+def pbs_root(adapter_dir: Path):
+    """Return the PBS checkout an adapter is installed in as adapters/<name>, or None."""
+    root = adapter_dir.resolve().parent.parent
+    if adapter_dir.resolve().parent.name == "adapters" and (root / "openrtb_ext").is_dir():
+        return root
+    return None
+
+
+def check_adapter(adapter_dir: Path, template: str = "prebid-adapter") -> list:
+    """Report how an adapter directory has drifted from the template.
+
+    Works on a generated adapter as well as one installed in PBS, where the
+    params struct lives in openrtb_ext/imp_<name>.go and the schema in the
+    PBS static/ directory.
+    """
+    sources = {p: p.read_text() for p in adapter_dir.rglob("*.go") if not p.name.endswith("_test.go")}
+    tests = "".join(p.read_text() for p in adapter_dir.rglob("*_test.go"))
+    schemas = sorted((adapter_dir / "static" / "bidder-params").glob("*.json"))
+    root = pbs_root(adapter_dir)
+    if root:
+        params = root / "openrtb_ext" / f"imp_{adapter_dir.resolve().name}.go"
+        if params.exists():
+            sources[params] = params.read_text()
+        schema = root / "static" / "bidder-params" / f"{adapter_dir.resolve().name}.json"
+        if schema.exists():
+            schemas.append(schema)
+    code = "".join(sources.values())
+    drift = []
+
+    defined = func_signatures(code)
+    for name, expected in template_signatures(get_templates_dir() / template).items():
+        if name not in defined:
+            drift.append(f"missing func {name}")
+        elif defined[name] != expected:
+            drift.append(f"func {name} has signature {defined[name]}, expected {expected}")
+    if "func TestJsonSamples(" not in tests and "func TestMakeBidsTable(" not in tests:
+        drift.append("missing TestJsonSamples or table-driven TestMakeBidsTable")
+
+    tags, aliases = {}, {}
+    for source in sources.values():
+        tags.update(params_tags(source))
+        aliases.update(params_aliases(source))
+    if not tags:
+        drift.append("missing ExtImp params struct")
+    if not schemas:
+        drift.append("missing static/bidder-params schema")
+    if tags and schemas:
+        schema = json.loads(schemas[0].read_text())
+        properties = set(schema.get("properties", {}))
+        for name in sorted(properties - set(tags) - set(aliases)):
+            drift.append(f"schema property {name} has no params field")
+        for name in sorted(set(tags) - properties):
+            drift.append(f"params field {name} is not in the schema")
+        for name in sorted(set(aliases) - properties):
+            drift.append(f"param alias {name} is not in the schema")
+        required = schema_required_groups(schema)
+        for name in sorted(set(tags) & properties):
+            if tags[name] != (name in required):
+                drift.append(f"params field {name} required={tags[name]} but schema required={name in required}")
+                continue
+            spellings = {name} | {legacy for legacy, canonical in aliases.items() if canonical == name}
+            if tags[name] and required[name] != spellings:
+                drift.append(f"params field {name} is required as any of {sorted(required[name])}, expected {sorted(spellings)}")
+    return drift
+
+
+def check_command(adapter_dir: str) -> bool:
+    path = Path(adapter_dir)
+    if not path.is_dir():
+        print(f"❌ '{adapter_dir}' is not a directory")
+        return False
+    drift = check_adapter(path)
+    if not drift:
+        print(f"✅ {adapter_dir} matches the prebid-adapter template")
+        return True
+    print(f"⚠️  {adapter_dir} has drifted from the prebid-adapter template:")
+    for item in drift:
+        print(f"  - {item}")
+    return False
+
+
 def main():
     if len(sys.argv) < 2:
         show_help()
@@ -182,16 +619,59 @@ def main():
         show_help()
         return
     
+    if sys.argv[1] == "check":
+        if len(sys.argv) < 3:
+            print("Usage: claude-new check <adapter-dir>")
+            sys.exit(1)
+        sys.exit(0 if check_command(sys.argv[2]) else 1)
+
     if len(sys.argv) < 3:
         print("❌ Missing project name")
-        print("Usage: claude-new <template> <name> [description]")
+        print("Usage: claude-new <template> <name> [description] [options]")
         return
     
-    template = sys.argv[1]
-    name = sys.argv[2]
-    description = sys.argv[3] if len(sys.argv) > 3 else None
+    parser = argparse.ArgumentParser(prog="claude-new", add_help=False)
+    parser.add_argument("template")
+    parser.add_argument("name")
+    parser.add_argument("description", nargs="?")
+    parser.add_argument("--param-alias", dest="param_aliases", action="append", default=[])
+    parser.add_argument("--param-default", dest="param_defaults", action="append", default=[])
+    parser.add_argument("--module-path")
+    parser.add_argument("--openrtb-version")
+    parser.add_argument("--alias-builder", action="store_true")
+    parser.add_argument("--with-usersync", action="store_true")
+    parser.add_argument("--with-replay", action="store_true")
+    parser.add_argument("--config-field", dest="config_fields", action="append", default=[])
+    parser.add_argument("--test-style")
+    parser.add_argument("--test-mode", action="store_true")
+    parser.add_argument("--with-helpers", action="store_true")
+    parser.add_argument("--with-java", action="store_true")
+    parser.add_argument("--license")
+    args = parser.parse_args(sys.argv[1:])
+
+    license_text = None
+    if args.license:
+        try:
+            license_text = Path(args.license).read_text()
+        except OSError as e:
+            print(f"❌ Cannot read --license file: {e}")
+            sys.exit(1)
     
-    generate_project(template, name, description)
+    generate_project(args.template, args.name, args.description, {
+        "param_aliases": args.param_aliases,
+        "param_defaults": args.param_defaults,
+        "module_path": args.module_path,
+        "openrtb_version": args.openrtb_version,
+        "alias_builder": args.alias_builder,
+        "with_usersync": args.with_usersync,
+        "with_replay": args.with_replay,
+        "config_fields": args.config_fields,
+        "test_style": args.test_style,
+        "test_mode": args.test_mode,
+        "with_helpers": args.with_helpers,
+        "with_java": args.with_java,
+        "license": license_text,
+    })
 
 
 if __name__ == "__main__":
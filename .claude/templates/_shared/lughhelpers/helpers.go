@@ -0,0 +1,64 @@
+// Package lughhelpers holds code shared by the adapters lugh generates. It is
+// emitted once beside them and belongs in adapters/internal/lughhelpers, so
+// every generated adapter imports the same copy.
+package lughhelpers
+
+import (
+	"cmp"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// RateCache looks up each currency pair in the core's rates once. Create one
+// per MakeRequests or MakeBids call: rates can change between auctions.
+type RateCache struct {
+	reqInfo *adapters.ExtraRequestInfo
+	rates   map[[2]string]float64
+}
+
+// NewRateCache returns an empty cache over reqInfo's conversions
+func NewRateCache(reqInfo *adapters.ExtraRequestInfo) *RateCache {
+	return &RateCache{reqInfo: reqInfo, rates: make(map[[2]string]float64)}
+}
+
+// Convert converts value between currencies. Failed lookups are not cached.
+func (c *RateCache) Convert(value float64, from, to string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+	key := [2]string{from, to}
+	rate, ok := c.rates[key]
+	if !ok {
+		var err error
+		if rate, err = c.reqInfo.ConvertCurrency(1, from, to); err != nil {
+			return 0, err
+		}
+		c.rates[key] = rate
+	}
+	return value * rate, nil
+}
+
+// ConvertFloor returns imp.bidfloor in currency. bidfloorcur defaults to USD
+// as in OpenRTB, and a zero floor needs no rate.
+func ConvertFloor(rates *RateCache, imp *openrtb2.Imp, currency string) (float64, error) {
+	if imp.BidFloor == 0 {
+		return 0, nil
+	}
+	return rates.Convert(imp.BidFloor, cmp.Or(imp.BidFloorCur, "USD"), currency)
+}
+
+// BidMeta builds the prebid meta the core reads from bid.adomain and bid.cat,
+// or returns nil when the bid carries neither
+func BidMeta(bid *openrtb2.Bid) *openrtb_ext.ExtBidPrebidMeta {
+	if len(bid.ADomain) == 0 && len(bid.Cat) == 0 {
+		return nil
+	}
+	meta := &openrtb_ext.ExtBidPrebidMeta{AdvertiserDomains: bid.ADomain}
+	if len(bid.Cat) > 0 {
+		meta.PrimaryCategoryID = bid.Cat[0]
+		meta.SecondaryCategoryIDs = bid.Cat[1:]
+	}
+	return meta
+}
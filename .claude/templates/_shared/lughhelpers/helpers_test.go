@@ -0,0 +1,118 @@
+package lughhelpers
+
+import (
+	"slices"
+	"testing"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/currency"
+)
+
+func TestConvertFloor(t *testing.T) {
+	reqInfo := adapters.NewExtraRequestInfo(currency.NewRates(map[string]map[string]float64{
+		"EUR": {"USD": 1.25},
+	}))
+
+	tests := []struct {
+		name    string
+		imp     openrtb2.Imp
+		want    float64
+		wantErr bool
+	}{
+		{name: "converted", imp: openrtb2.Imp{BidFloor: 2, BidFloorCur: "EUR"}, want: 2.5},
+		{name: "USD by default", imp: openrtb2.Imp{BidFloor: 2}, want: 2},
+		{name: "no floor", imp: openrtb2.Imp{BidFloorCur: "GBP"}, want: 0},
+		{name: "missing rate", imp: openrtb2.Imp{BidFloor: 2, BidFloorCur: "GBP"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertFloor(NewRateCache(&reqInfo), &tt.imp, "USD")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertFloor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ConvertFloor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingRates counts rate lookups reaching the core's conversions
+type countingRates struct {
+	*currency.Rates
+	lookups int
+}
+
+func (r *countingRates) GetRate(from, to string) (float64, error) {
+	r.lookups++
+	return r.Rates.GetRate(from, to)
+}
+
+func TestRateCache(t *testing.T) {
+	rates := &countingRates{Rates: currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.25}})}
+	reqInfo := adapters.NewExtraRequestInfo(rates)
+	cache := NewRateCache(&reqInfo)
+
+	for _, value := range []float64{1, 2, 4} {
+		got, err := cache.Convert(value, "EUR", "USD")
+		if err != nil || got != value*1.25 {
+			t.Errorf("Convert(%v) = %v, %v", value, got, err)
+		}
+	}
+	if _, err := cache.Convert(1, "USD", "USD"); err != nil {
+		t.Errorf("same-currency Convert failed: %v", err)
+	}
+	if rates.lookups != 1 {
+		t.Errorf("expected 1 rate lookup, got %d", rates.lookups)
+	}
+}
+
+// BenchmarkConvertFloor reports rate lookups per call for a request of 20
+// EUR-floored imps, with and without sharing a RateCache across the imps
+func BenchmarkConvertFloor(b *testing.B) {
+	imps := make([]openrtb2.Imp, 20)
+	for i := range imps {
+		imps[i] = openrtb2.Imp{BidFloor: float64(i + 1), BidFloorCur: "EUR"}
+	}
+
+	for _, bench := range []struct {
+		name   string
+		shared bool
+	}{
+		{name: "per-imp"},
+		{name: "per-call", shared: true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			rates := &countingRates{Rates: currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.25}})}
+			reqInfo := adapters.NewExtraRequestInfo(rates)
+			for n := 0; n < b.N; n++ {
+				cache := NewRateCache(&reqInfo)
+				for i := range imps {
+					if !bench.shared {
+						cache = NewRateCache(&reqInfo)
+					}
+					if _, err := ConvertFloor(cache, &imps[i], "USD"); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(rates.lookups)/float64(b.N), "lookups/op")
+		})
+	}
+}
+
+func TestBidMeta(t *testing.T) {
+	if meta := BidMeta(&openrtb2.Bid{}); meta != nil {
+		t.Errorf("expected nil meta for a bare bid, got %+v", meta)
+	}
+
+	meta := BidMeta(&openrtb2.Bid{ADomain: []string{"advertiser.com"}, Cat: []string{"IAB1", "IAB2"}})
+	if !slices.Equal(meta.AdvertiserDomains, []string{"advertiser.com"}) {
+		t.Errorf("AdvertiserDomains = %v", meta.AdvertiserDomains)
+	}
+	if meta.PrimaryCategoryID != "IAB1" || !slices.Equal(meta.SecondaryCategoryIDs, []string{"IAB2"}) {
+		t.Errorf("categories = %q %v", meta.PrimaryCategoryID, meta.SecondaryCategoryIDs)
+	}
+}
@@ -1,29 +1,551 @@
 package {{NAME_LOWER}}
 
 import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"crypto/rand"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"mime"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/prebid/openrtb/v20/openrtb2"
-	"github.com/prebid/prebid-server/v2/adapters"
-	"github.com/prebid/prebid-server/v2/config"
-	"github.com/prebid/prebid-server/v2/errortypes"
-	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"{{OPENRTB_MODULE}}/adcom1"
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{OPENRTB_MODULE}}/openrtb3"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/errortypes"
+	"{{PBS_MODULE}}/openrtb_ext"
 )
 
+// FailoverHeader marks a request sent to a failover endpoint; its value is the
+// primary endpoint the request was copied from.
+const FailoverHeader = "X-Failover-For"
+
+// Headers carrying request.ext.prebid.server when extraInfo.ServerInfoHeaders is set
+const (
+	ServerURLHeader        = "X-Prebid-Server-Url"
+	ServerDataCenterHeader = "X-Prebid-Server-Datacenter"
+)
+
+// TMaxBudgetHeader carries each request's share of request.tmax, in
+// milliseconds, when extraInfo.SplitTMax divides it across split requests
+const TMaxBudgetHeader = "X-Prebid-Tmax-Budget"
+
+// RequestIDHeader carries request.id, or source.tid when the id is empty, when
+// extraInfo.RequestIDHeader is set, so auctions can be matched to endpoint logs
+const RequestIDHeader = "X-Request-ID"
+
+// maxExactPriceLen caps the decimal price texts priceBelow compares exactly
+const maxExactPriceLen = 64
+
+// failoverClaimTTL bounds how long MakeBids remembers which response answered
+// each imp of an auction sent with failover copies; it outlasts any tmax
+const failoverClaimTTL = 10 * time.Second
+
+// defaultCooldown is recommended after a 429 or 5xx that carries no Retry-After
+const defaultCooldown = 5 * time.Second
+
+// CooldownError is returned for 429 and 5xx responses. Cooldown is how long the
+// endpoint should be left alone, taken from Retry-After when the server sent one,
+// so the core or ops tooling can back off instead of retrying immediately.
+type CooldownError struct {
+	errortypes.BadServerResponse
+	Cooldown time.Duration
+}
+
+// Code reports the BadServerResponse error code, so errortypes.ReadCode and
+// the core's metrics classify a cooldown like any other bad response
+func (err *CooldownError) Code() int {
+	return errortypes.BadServerResponseErrorCode
+}
+
+// Unwrap exposes the embedded BadServerResponse to errors.As
+func (err *CooldownError) Unwrap() error {
+	return &err.BadServerResponse
+}
+
 type adapter struct {
-	endpoint string
+	bidderName         openrtb_ext.BidderName
+	endpoint           string
+	extraInfo          extraInfo
+	blockedAdmPatterns []*regexp.Regexp
+	translateCategory  categoryTranslator
+	recordBid          BidRecorder
+	newTID             func() (string, error)
+	responseDecoders   map[string]ResponseDecoder
+	failoverClaims     *failoverClaims
+}
+
+// ResponseDecoder decodes a response body sent with a non-JSON Content-Type,
+// such as protobuf. DecoderBuilder registers decoders by media type; any
+// other Content-Type is decoded as JSON.
+type ResponseDecoder func(body []byte) (openrtb2.BidResponse, error)
+
+// BidRecorder observes each bid handed to the core. Labels use the
+// Prometheus-style keys below so they can feed a CounterVec directly.
+type BidRecorder func(labels map[string]string)
+
+// Label keys passed to BidRecorder
+const (
+	BidLabelBidder    = "bidder"
+	BidLabelMediaType = "media_type"
+	BidLabelSeat      = "seat"
+)
+
+// discardBid is the recorder Builder installs; use MetricsBuilder to wire one
+// to a metrics backend.
+func discardBid(map[string]string) {}
+
+// categoryTranslator maps a bid.cat code to the publisher's taxonomy. It
+// reports false when the code has no mapping and is left unchanged.
+type categoryTranslator func(category string) (string, bool)
+
+// passthroughCategory is the default translator: bid.cat is sent on as-is
+func passthroughCategory(category string) (string, bool) {
+	return category, false
+}
+
+// mappedCategories translates bid.cat codes through a fixed lookup table
+func mappedCategories(categories map[string]string) categoryTranslator {
+	return func(category string) (string, bool) {
+		translated, ok := categories[category]
+		if !ok {
+			return category, false
+		}
+		return translated, true
+	}
+}
+
+// extraInfo holds the optional settings read from config.Adapter.ExtraAdapterInfo
+type extraInfo struct {
+	// StripImpExtPrebid removes imp.ext.prebid before the request is sent
+	StripImpExtPrebid bool `json:"stripImpExtPrebid,omitempty"`
+
+	// PreciseBidPrice decodes bid prices as json.Number and keeps the
+	// endpoint's decimal text, so minBidPrice is compared exactly; prices are
+	// only converted to float64 for the typed bids. Prices sent as JSON
+	// strings, like "2.50", are accepted, and one that does not parse drops
+	// only its bid instead of failing the whole response.
+	PreciseBidPrice bool `json:"preciseBidPrice,omitempty"`
+
+	// DedupImps collapses imps that only differ by ID into a single outgoing
+	// imp; MakeBids copies the bid back onto each collapsed imp
+	DedupImps bool `json:"dedupImps,omitempty"`
+
+	// DedupBids keeps only the highest-priced bid for each bid.id, for
+	// endpoints that return the same bid under several seats
+	DedupBids bool `json:"dedupBids,omitempty"`
+
+	// RegionEndpoints overrides the endpoint for the config.Server data center
+	RegionEndpoints map[string]string `json:"regionEndpoints,omitempty"`
+
+	// DeviceTypeEndpoints routes requests by device.devicetype, keyed by the
+	// AdCOM value (e.g. "3" for connected TV). The AMP endpoint still wins.
+	DeviceTypeEndpoints map[adcom1.DeviceType]string `json:"deviceTypeEndpoints,omitempty"`
+
+	// NativeAdmObject accepts native adm returned as a JSON object and
+	// re-encodes it as the string the core expects
+	NativeAdmObject bool `json:"nativeAdmObject,omitempty"`
+
+	// RewardedEndpoint receives the rewarded (imp.rwdd=1) imps in a separate request
+	RewardedEndpoint string `json:"rewardedEndpoint,omitempty"`
+
+	// InterstitialEndpoint receives the interstitial (imp.instl=1) imps in a
+	// separate request. Rewarded routing wins for imps that are both.
+	InterstitialEndpoint string `json:"interstitialEndpoint,omitempty"`
+
+	// EnforceResponseCurrency drops bids whose bid.ext.cur differs from the
+	// response currency. Rates are not available in MakeBids, so no conversion
+	// is attempted.
+	EnforceResponseCurrency bool `json:"enforceResponseCurrency,omitempty"`
+
+	// TMaxHeader names a header carrying request.tmax (milliseconds) so the
+	// endpoint can honor the auction budget
+	TMaxHeader string `json:"tmaxHeader,omitempty"`
+
+	// SplitTMax divides request.tmax evenly across the requests an auction is
+	// split into, for endpoints that serve them one after another. Each
+	// request's tmax is its share, also sent as TMaxBudgetHeader.
+	SplitTMax bool `json:"splitTmax,omitempty"`
+
+	// GzipMinBytes gzips request bodies larger than this many bytes. Zero
+	// disables compression.
+	GzipMinBytes int `json:"gzipMinBytes,omitempty"`
+
+	// DefaultImpExp is applied as imp.exp (seconds) when the imp does not set one
+	DefaultImpExp int64 `json:"defaultImpExp,omitempty"`
+
+	// FailoverEndpoints receive a copy of every request sent to the primary
+	// endpoint. The core has no retry hook and sends all RequestData
+	// concurrently, so MakeBids keeps the bids of whichever response answers
+	// an imp first and drops the others' bids on it (see failoverClaims).
+	FailoverEndpoints []string `json:"failoverEndpoints,omitempty"`
+
+	// GeoPrecision rounds device.geo and user.geo lat/lon to this many
+	// decimals. Zero disables rounding.
+	GeoPrecision int `json:"geoPrecision,omitempty"`
+
+	// FloorCurrency converts every imp.bidfloor to this currency with the
+	// core's rates, for endpoints that price floors in one currency. Imps
+	// whose floor has no rate are dropped with a BadInput.
+	FloorCurrency string `json:"floorCurrency,omitempty"`
+
+	// FloorPrecision rounds imp.bidfloor up to this many decimals for
+	// endpoints that reject longer floors. Rounding up keeps the publisher's
+	// floor honored. Zero disables rounding.
+	FloorPrecision int `json:"floorPrecision,omitempty"`
+
+	// BlockListMode controls request.bcat/badv/bapp: "forward" (default)
+	// sends them as-is, "drop" removes them and "merge" adds the defaults below
+	BlockListMode string   `json:"blockListMode,omitempty"`
+	DefaultBCat   []string `json:"defaultBCat,omitempty"`
+	DefaultBAdv   []string `json:"defaultBAdv,omitempty"`
+	DefaultBApp   []string `json:"defaultBApp,omitempty"`
+
+	// StrictPosition warns about banner or video imps without a known pos
+	StrictPosition bool `json:"strictPosition,omitempty"`
+
+	// BlockedAdmPatterns are regular expressions; bids whose adm matches any
+	// of them are dropped
+	BlockedAdmPatterns []string `json:"blockedAdmPatterns,omitempty"`
+
+	// ChannelHeader names a header carrying request.ext.prebid.channel.name
+	ChannelHeader string `json:"channelHeader,omitempty"`
+
+	// AMPEndpoint receives requests from the amp channel
+	AMPEndpoint string `json:"ampEndpoint,omitempty"`
+
+	// SandboxEndpoint receives every request with test=1, whole: rewarded,
+	// interstitial and failover routing do not apply to test traffic
+	SandboxEndpoint string `json:"sandboxEndpoint,omitempty"`
+
+	// SingleSizeBanner sends one imp per format for banner-only imps with
+	// several sizes, for endpoints that accept a single size per imp
+	SingleSizeBanner bool `json:"singleSizeBanner,omitempty"`
+
+	// AcceptLanguage sets the Accept-Language header from device.language,
+	// falling back to the site or app content language
+	AcceptLanguage bool `json:"acceptLanguage,omitempty"`
+
+	// FirstPriceMode is for endpoints that only run first-price auctions:
+	// "coerce" sends at=1 whatever the request says, "reject" fails requests
+	// that ask for another auction type. Unset forwards request.at as-is.
+	FirstPriceMode string `json:"firstPriceMode,omitempty"`
+
+	// CategoryMap translates bid.cat codes (e.g. IAB1-1) to the publisher's
+	// primary category ids. Unmapped codes pass through unchanged.
+	CategoryMap map[string]string `json:"categoryMap,omitempty"`
+
+	// RequireBuyerUID skips the request entirely when user.buyeruid is
+	// missing, for endpoints that reject unsynced users with a 400
+	RequireBuyerUID bool `json:"requireBuyerUid,omitempty"`
+
+	// StrictTLS makes Builder reject any configured endpoint that is not https
+	StrictTLS bool `json:"strictTLS,omitempty"`
+
+	// ServerInfoHeaders sends request.ext.prebid.server's external URL and
+	// datacenter as the Server*Header headers
+	ServerInfoHeaders bool `json:"serverInfoHeaders,omitempty"`
+
+	// DefaultKeywords are appended to site.keywords or app.keywords when
+	// missing. The request's own keywords are always forwarded.
+	DefaultKeywords []string `json:"defaultKeywords,omitempty"`
+
+	// BidMetrics passes every returned bid's labels to the BidRecorder given
+	// to MetricsBuilder; with the plain Builder the labels are discarded
+	BidMetrics bool `json:"bidMetrics,omitempty"`
+
+	// GenerateTIDs fills a missing source.tid or imp.ext.tid with a random
+	// UUID. Transaction ids already on the request are always forwarded.
+	GenerateTIDs bool `json:"generateTids,omitempty"`
+
+	// DefaultSourceFD fills a missing source.fd (0 exchange, 1 upstream
+	// source decides) for endpoints that key on it. A request's own fd wins.
+	DefaultSourceFD *int8 `json:"defaultSourceFd,omitempty"`
+
+	// SupportedMediaTypes lists the imp objects the endpoint accepts
+	// (banner, video, audio, native). Others are stripped from multi-format
+	// imps and imps left with none are dropped. Empty sends every type.
+	SupportedMediaTypes []string `json:"supportedMediaTypes,omitempty"`
+
+	// MaxImps caps the imps sent per auction, trimming the lowest
+	// imp.bidfloor first. Floors are compared as-is, whatever their currency.
+	MaxImps int `json:"maxImps,omitempty"`
+
+	// EnforceLMT strips device identifiers (ifa, hashed device, platform and
+	// MAC ids) when device.lmt=1; LMTDropUser removes the user object too
+	EnforceLMT  bool `json:"enforceLmt,omitempty"`
+	LMTDropUser bool `json:"lmtDropUser,omitempty"`
+
+	// RequiredContentFields names the site/app.content fields CTV demand
+	// needs (see contentFields). Missing ones are reported as warnings; the
+	// content object is always forwarded as-is.
+	RequiredContentFields []string `json:"requiredContentFields,omitempty"`
+
+	// KeepAlive sends an explicit Connection: keep-alive for endpoints that
+	// close HTTP/1.1 connections without it
+	KeepAlive bool `json:"keepAlive,omitempty"`
+
+	// UserAgent is sent as the User-Agent header, e.g. "prebid-server/{{NAME}}",
+	// so the endpoint can identify Prebid Server traffic
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Accept replaces the default "application/json" Accept header, for
+	// endpoints that negotiate other formats (see DecoderBuilder)
+	Accept string `json:"accept,omitempty"`
+
+	// RequestIDHeader sends RequestIDHeader for tracing auctions end to end
+	RequestIDHeader bool `json:"requestIdHeader,omitempty"`
+
+	// SplitByFloorCurrency sends imps with different bidfloorcur in separate
+	// requests, each with cur set to that currency, for endpoints that price
+	// in the request currency
+	SplitByFloorCurrency bool `json:"splitByFloorCurrency,omitempty"`
+
+	// DefaultDisplayManager and DefaultDisplayManagerVer fill an app imp's
+	// missing displaymanager/displaymanagerver. Values on the imp win.
+	DefaultDisplayManager    string `json:"defaultDisplayManager,omitempty"`
+	DefaultDisplayManagerVer string `json:"defaultDisplayManagerVer,omitempty"`
+
+	// MaxResponseBytes rejects response bodies larger than this before they
+	// are unmarshalled. Zero disables the limit.
+	MaxResponseBytes int `json:"maxResponseBytes,omitempty"`
+
+	// ValidateSKAdN warns about imp.ext.skadn blocks missing the fields iOS
+	// demand needs (see validateSKAdN). The block is forwarded either way.
+	ValidateSKAdN bool `json:"validateSkadn,omitempty"`
+
+	// ValidateMetrics warns about imp.metric entries without a type, or with
+	// a viewability value outside 0-1. Metrics are forwarded either way.
+	ValidateMetrics bool `json:"validateMetrics,omitempty"`
+
+	// ValidateVAST drops video bids whose adm is not well-formed VAST XML,
+	// with a Warning, before the core caches them for CTV players. Bids
+	// without adm are served from nurl and not checked.
+	ValidateVAST bool `json:"validateVast,omitempty"`
+
+	// AudioProtocols lists the audio protocols (AdCOM creative subtypes) the
+	// endpoint accepts. Others are removed from imp.audio.protocols, and an
+	// audio imp left with none is dropped. Empty forwards protocols as-is.
+	AudioProtocols []adcom1.MediaCreativeSubtype `json:"audioProtocols,omitempty"`
+
+	// StatusMapping maps an endpoint's non-standard status codes to the
+	// standard ones MakeBids understands, e.g. {"299":204,"422":400}.
+	// Unmapped codes are interpreted as-is.
+	StatusMapping map[int]int `json:"statusMapping,omitempty"`
+
+	// SortDeals returns deal bids (bid.dealid set) before open-market ones,
+	// higher bid.ext.dealtier first. The tier is also set as DealPriority.
+	SortDeals bool `json:"sortDeals,omitempty"`
+
+	// DropDeprecatedFields removes fields OpenRTB 2.6 deprecates for
+	// endpoints that reject them (see dropDeprecatedImpFields and
+	// dropDeprecatedUserFields for user.yob and user.gender)
+	DropDeprecatedFields bool `json:"dropDeprecatedFields,omitempty"`
+
+	// NativeRegs forwards consent signals in the 2.6 regs fields, copying
+	// legacy regs.ext.gdpr and regs.ext.us_privacy where the native one is unset
+	NativeRegs bool `json:"nativeRegs,omitempty"`
+
+	// AllowedSeats keeps only bids from these seatbid.seat values for
+	// endpoints that multiplex seats; other seats are dropped with a Warning.
+	// Empty accepts every seat.
+	AllowedSeats []string `json:"allowedSeats,omitempty"`
+
+	// MinBidPrice drops bids priced below it, in the response currency, with
+	// a Warning. Zero disables the check.
+	MinBidPrice float64 `json:"minBidPrice,omitempty"`
+
+	// PreferMType types bids by bid.mtype when the endpoint sets it, ahead
+	// of the imp's media objects, so a video bid on a banner and video imp
+	// is typed video. Without it bids are typed from the imp alone.
+	PreferMType bool `json:"preferMtype,omitempty"`
+
+	// MaxAdmBytes drops bids whose adm is longer than this many bytes, with
+	// a Warning. Zero disables the check.
+	MaxAdmBytes int `json:"maxAdmBytes,omitempty"`
+
+	// Config holds the endpoint settings declared with --config-field when
+	// the adapter was generated (see adapterConfig)
+	Config adapterConfig `json:"config"`
+}
+
+// contentFields reports, per RequiredContentFields name, whether a content
+// object sets that field
+var contentFields = map[string]func(content *openrtb2.Content) bool{
+	"title":    func(content *openrtb2.Content) bool { return content.Title != "" },
+	"series":   func(content *openrtb2.Content) bool { return content.Series != "" },
+	"season":   func(content *openrtb2.Content) bool { return content.Season != "" },
+	"episode":  func(content *openrtb2.Content) bool { return content.Episode != 0 },
+	"genre":    func(content *openrtb2.Content) bool { return content.Genre != "" },
+	"language": func(content *openrtb2.Content) bool { return content.Language != "" },
+	"len":      func(content *openrtb2.Content) bool { return content.Len != 0 },
 }
 
+// splitImpIDSeparator joins the original imp ID and the format index of the
+// imps created by splitBannerFormats
+const splitImpIDSeparator = "__size"
+
+// Block list modes accepted in extraInfo.BlockListMode
+const (
+	blockListForward = "forward"
+	blockListDrop    = "drop"
+	blockListMerge   = "merge"
+)
+
+// First-price modes accepted in extraInfo.FirstPriceMode
+const (
+	firstPriceCoerce = "coerce"
+	firstPriceReject = "reject"
+)
+
 // Builder builds a new instance of the {{NAME}} adapter
 func Builder(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+	var info extraInfo
+	if config.ExtraAdapterInfo != "" {
+		if err := json.Unmarshal([]byte(config.ExtraAdapterInfo), &info); err != nil {
+			return nil, fmt.Errorf("invalid extra_info for {{NAME}}: %w", err)
+		}
+	}
+
+	if err := checkParamDefaults(); err != nil {
+		return nil, err
+	}
+
+	switch info.BlockListMode {
+	case "", blockListForward, blockListDrop, blockListMerge:
+	default:
+		return nil, fmt.Errorf("invalid extra_info for {{NAME}}: unknown blockListMode %q", info.BlockListMode)
+	}
+
+	for _, mediaType := range info.SupportedMediaTypes {
+		switch openrtb_ext.BidType(mediaType) {
+		case openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeAudio, openrtb_ext.BidTypeNative:
+		default:
+			return nil, fmt.Errorf("invalid extra_info for {{NAME}}: unknown supportedMediaTypes entry %q", mediaType)
+		}
+	}
+
+	for _, field := range info.RequiredContentFields {
+		if _, ok := contentFields[field]; !ok {
+			return nil, fmt.Errorf("invalid extra_info for {{NAME}}: unknown requiredContentFields entry %q", field)
+		}
+	}
+
+	blockedAdmPatterns := make([]*regexp.Regexp, 0, len(info.BlockedAdmPatterns))
+	for _, pattern := range info.BlockedAdmPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extra_info for {{NAME}}: blockedAdmPatterns: %w", err)
+		}
+		blockedAdmPatterns = append(blockedAdmPatterns, re)
+	}
+
+	switch info.FirstPriceMode {
+	case "", firstPriceCoerce, firstPriceReject:
+	default:
+		return nil, fmt.Errorf("invalid extra_info for {{NAME}}: unknown firstPriceMode %q", info.FirstPriceMode)
+	}
+
+	for from, to := range info.StatusMapping {
+		if http.StatusText(to) == "" {
+			return nil, fmt.Errorf("invalid extra_info for {{NAME}}: statusMapping maps %d to unknown status %d", from, to)
+		}
+	}
+
+	if info.StrictTLS {
+		for _, endpoint := range configuredEndpoints(config.Endpoint, info) {
+			if u, err := url.Parse(endpoint); err != nil || u.Scheme != "https" {
+				return nil, fmt.Errorf("invalid endpoint for {{NAME}}: strictTLS requires https, got %q", endpoint)
+			}
+		}
+	}
+
+	endpoint := config.Endpoint
+	if regionEndpoint, ok := info.RegionEndpoints[server.DataCenter]; ok && server.DataCenter != "" {
+		endpoint = regionEndpoint
+	}
+
+	translateCategory := passthroughCategory
+	if len(info.CategoryMap) > 0 {
+		translateCategory = mappedCategories(info.CategoryMap)
+	}
+
 	bidder := &adapter{
-		endpoint: config.Endpoint,
+		bidderName:         bidderName,
+		endpoint:           endpoint,
+		extraInfo:          info,
+		blockedAdmPatterns: blockedAdmPatterns,
+		translateCategory:  translateCategory,
+		recordBid:          discardBid,
+		newTID:             randomTID,
+		responseDecoders:   map[string]ResponseDecoder{},
+		failoverClaims:     newFailoverClaims(),
 	}
 	return bidder, nil
 }
 
+// MetricsBuilder returns a Builder whose adapters pass bid labels to record
+// when bidMetrics is set in extra_info. Register it in place of Builder in
+// exchange/adapter_builders.go.
+func MetricsBuilder(record BidRecorder) adapters.Builder {
+	return func(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+		bidder, err := Builder(bidderName, config, server)
+		if err != nil {
+			return nil, err
+		}
+		bidder.(*adapter).recordBid = record
+		return bidder, nil
+	}
+}
+
+// DecoderBuilder returns a Builder whose adapters decode responses with the
+// decoder registered for their Content-Type media type, e.g.
+// "application/x-protobuf". Register it in place of Builder in
+// exchange/adapter_builders.go.
+func DecoderBuilder(decoders map[string]ResponseDecoder) adapters.Builder {
+	return func(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+		bidder, err := Builder(bidderName, config, server)
+		if err != nil {
+			return nil, err
+		}
+		for mediaType, decode := range decoders {
+			bidder.(*adapter).responseDecoders[strings.ToLower(mediaType)] = decode
+		}
+		return bidder, nil
+	}
+}
+
+// configuredEndpoints lists every endpoint the adapter may send requests to
+func configuredEndpoints(endpoint string, info extraInfo) []string {
+	endpoints := []string{endpoint}
+	for _, regionEndpoint := range info.RegionEndpoints {
+		endpoints = append(endpoints, regionEndpoint)
+	}
+	for _, deviceEndpoint := range info.DeviceTypeEndpoints {
+		endpoints = append(endpoints, deviceEndpoint)
+	}
+	for _, extra := range []string{info.RewardedEndpoint, info.InterstitialEndpoint, info.AMPEndpoint, info.SandboxEndpoint} {
+		if extra != "" {
+			endpoints = append(endpoints, extra)
+		}
+	}
+	return append(endpoints, info.FailoverEndpoints...)
+}
+
 // MakeRequests creates the HTTP requests for the bidder
 func (a *adapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	var errors []error
@@ -33,10 +555,37 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.E
 		return nil, []error{&errortypes.BadInput{Message: "No impressions in request"}}
 	}
 
-	// Process each impression
+	if a.extraInfo.RequireBuyerUID && (request.User == nil || request.User.BuyerUID == "") {
+		return nil, []error{&errortypes.BadInput{
+			Message: "Missing user.buyeruid, the endpoint requires a synced user",
+		}}
+	}
+
+	// A request without at is treated as unspecified rather than second price
+	if a.extraInfo.FirstPriceMode == firstPriceReject && request.AT > 1 {
+		return nil, []error{&errortypes.BadInput{
+			Message: fmt.Sprintf("Unsupported auction type %d, only first price (at=1) is supported", request.AT),
+		}}
+	}
+
+	// Dedup the core's imps before any transform: generated tids or a stripped
+	// imp.ext.prebid would make the outgoing imps disagree with the mapping
+	// MakeBids rebuilds from request.Imp
+	if a.extraInfo.DedupImps {
+		withoutDuplicates := *request
+		withoutDuplicates.Imp, _ = dedupImps(request.Imp)
+		request = &withoutDuplicates
+	}
+
+	// One cache per call: each currency pair is looked up once per auction
+	rates := newRateCache(reqInfo)
+
+	// Process each impression; only imps with valid params are sent
+	validImps := make([]openrtb2.Imp, 0, len(request.Imp))
 	for i := range request.Imp {
-		imp := &request.Imp[i]
-		
+		// Work on a copy so the core's imp, passed again to MakeBids, stays intact
+		imp := request.Imp[i]
+
 		// Extract bidder params
 		var bidderExt adapters.ExtImpBidder
 		if err := json.Unmarshal(imp.Ext, &bidderExt); err != nil {
@@ -47,94 +596,1888 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.E
 		}
 
 		var impExt openrtb_ext.ExtImp{{NAME}}
-		if err := json.Unmarshal(bidderExt.Bidder, &impExt); err != nil {
+		if err := decodeParams(bidderExt.Bidder, &impExt); err != nil {
 			errors = append(errors, &errortypes.BadInput{
 				Message: fmt.Sprintf("Error unmarshalling bidder ext: %s", err.Error()),
 			})
 			continue
 		}
 
+		if err := impExt.Validate(); err != nil {
+			errors = append(errors, &errortypes.BadInput{
+				Message: fmt.Sprintf("imp %s: invalid bidder params: %s", imp.ID, err.Error()),
+			})
+			continue
+		}
+
+		if len(a.extraInfo.SupportedMediaTypes) > 0 && !stripUnsupportedMediaTypes(&imp, a.extraInfo.SupportedMediaTypes) {
+			errors = append(errors, &errortypes.BadInput{
+				Message: fmt.Sprintf("imp %s: no supported media type, the endpoint accepts %v", imp.ID, a.extraInfo.SupportedMediaTypes),
+			})
+			continue
+		}
+
+		if imp.Video != nil {
+			errors = append(errors, validateVideo(&imp)...)
+		}
+
+		if imp.Audio != nil {
+			if len(a.extraInfo.AudioProtocols) > 0 && !filterAudioProtocols(&imp, a.extraInfo.AudioProtocols) {
+				errors = append(errors, &errortypes.BadInput{
+					Message: fmt.Sprintf("imp %s: no supported audio protocol, the endpoint accepts %v", imp.ID, a.extraInfo.AudioProtocols),
+				})
+				continue
+			}
+			errors = append(errors, validateAudio(&imp)...)
+		}
+
+		if request.DOOH != nil {
+			if err := validateQty(&imp); err != nil {
+				errors = append(errors, err)
+				continue
+			}
+		}
+
+		if a.extraInfo.StrictPosition {
+			errors = append(errors, validatePosition(&imp)...)
+		}
+
+		if a.extraInfo.ValidateSKAdN {
+			errors = append(errors, validateSKAdN(&imp)...)
+		}
+
+		if a.extraInfo.ValidateMetrics {
+			errors = append(errors, validateMetrics(&imp)...)
+		}
+
+		// Rewarded inventory flagged only through Prebid is sent as imp.rwdd
+		if imp.Rwdd == 0 && bidderExt.Prebid != nil && bidderExt.Prebid.IsRewardedInventory != nil {
+			imp.Rwdd = *bidderExt.Prebid.IsRewardedInventory
+		}
+
+		if imp.Exp == 0 {
+			imp.Exp = a.extraInfo.DefaultImpExp
+		}
+
+		if request.App != nil {
+			if imp.DisplayManager == "" {
+				imp.DisplayManager = a.extraInfo.DefaultDisplayManager
+			}
+			if imp.DisplayManagerVer == "" {
+				imp.DisplayManagerVer = a.extraInfo.DefaultDisplayManagerVer
+			}
+		}
+
+		if a.extraInfo.StripImpExtPrebid {
+			ext, err := stripImpExtPrebid(imp.Ext)
+			if err != nil {
+				errors = append(errors, &errortypes.BadInput{
+					Message: fmt.Sprintf("Error rewriting imp.ext: %s", err.Error()),
+				})
+				continue
+			}
+			imp.Ext = ext
+		}
+
+		if a.extraInfo.DropDeprecatedFields {
+			dropDeprecatedImpFields(&imp)
+		}
+
+		if a.extraInfo.GenerateTIDs {
+			ext, err := withImpTID(imp.Ext, a.newTID)
+			if err != nil {
+				errors = append(errors, &errortypes.BadInput{
+					Message: fmt.Sprintf("Error rewriting imp.ext: %s", err.Error()),
+				})
+				continue
+			}
+			imp.Ext = ext
+		}
+
+		if a.extraInfo.FloorCurrency != "" {
+			floor, err := convertFloor(rates, &imp, a.extraInfo.FloorCurrency)
+			if err != nil {
+				errors = append(errors, &errortypes.BadInput{
+					Message: fmt.Sprintf("imp %s: cannot convert bidfloor to %s: %s", imp.ID, a.extraInfo.FloorCurrency, err.Error()),
+				})
+				continue
+			}
+			imp.BidFloor = floor
+			imp.BidFloorCur = a.extraInfo.FloorCurrency
+		}
+
+		if a.extraInfo.FloorPrecision > 0 {
+			imp.BidFloor = roundFloorUp(imp.BidFloor, a.extraInfo.FloorPrecision)
+		}
+
 		// TODO: Transform impression based on bidder params
+
+		validImps = append(validImps, imp)
 	}
 
-	// Serialize request
-	reqJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, []error{err}
+	if len(validImps) == 0 {
+		return nil, errors
+	}
+	if a.extraInfo.MaxImps > 0 && len(validImps) > a.extraInfo.MaxImps {
+		var trimmed []string
+		validImps, trimmed = trimImpsByFloor(validImps, a.extraInfo.MaxImps)
+		errors = append(errors, &errortypes.Warning{
+			Message: fmt.Sprintf("Trimmed imps %v beyond the maxImps cap of %d", trimmed, a.extraInfo.MaxImps),
+		})
 	}
+	filtered := *request
+	filtered.Imp = validImps
+	if a.extraInfo.FirstPriceMode == firstPriceCoerce {
+		filtered.AT = 1
+	}
+	if a.extraInfo.GenerateTIDs && (request.Source == nil || request.Source.TID == "") {
+		tid, err := a.newTID()
+		if err != nil {
+			return nil, append(errors, fmt.Errorf("generating source.tid: %w", err))
+		}
+		source := openrtb2.Source{}
+		if request.Source != nil {
+			source = *request.Source
+		}
+		source.TID = tid
+		filtered.Source = &source
+	}
+	if a.extraInfo.DefaultSourceFD != nil && (filtered.Source == nil || filtered.Source.FD == nil) {
+		source := openrtb2.Source{}
+		if filtered.Source != nil {
+			source = *filtered.Source
+		}
+		source.FD = a.extraInfo.DefaultSourceFD
+		filtered.Source = &source
+	}
+	request = &filtered
 
-	// Create HTTP request
-	headers := http.Header{}
-	headers.Add("Content-Type", "application/json;charset=utf-8")
-	headers.Add("Accept", "application/json")
+	if len(a.extraInfo.RequiredContentFields) > 0 {
+		errors = append(errors, validateContent(request, a.extraInfo.RequiredContentFields)...)
+	}
 
-	return []*adapters.RequestData{
-		{
-			Method:  "POST",
-			Uri:     a.endpoint,
-			Body:    reqJSON,
-			Headers: headers,
-			ImpIDs:  openrtb_ext.GetImpIDs(request.Imp),
-		},
-	}, errors
-}
+	if a.extraInfo.GeoPrecision > 0 {
+		request = reduceGeoPrecision(request, a.extraInfo.GeoPrecision)
+	}
 
-// MakeBids unpacks the server's response into Bids
-func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
-	if response.StatusCode == http.StatusNoContent {
-		return nil, nil
+	if a.extraInfo.EnforceLMT && request.Device != nil && request.Device.Lmt != nil && *request.Device.Lmt == 1 {
+		request = limitAdTracking(request, a.extraInfo.LMTDropUser)
 	}
 
-	if response.StatusCode == http.StatusBadRequest {
-		return nil, []error{&errortypes.BadInput{
-			Message: fmt.Sprintf("Bad request: %s", string(response.Body)),
-		}}
+	if a.extraInfo.DropDeprecatedFields && request.User != nil && (request.User.Yob != 0 || request.User.Gender != "") {
+		request = dropDeprecatedUserFields(request)
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return nil, []error{&errortypes.BadServerResponse{
-			Message: fmt.Sprintf("Unexpected status code: %d", response.StatusCode),
-		}}
+	if a.extraInfo.NativeRegs && request.Regs != nil && len(request.Regs.Ext) > 0 {
+		request = promoteRegsExt(request)
 	}
 
-	var bidResp openrtb2.BidResponse
-	if err := json.Unmarshal(response.Body, &bidResp); err != nil {
-		return nil, []error{&errortypes.BadServerResponse{
-			Message: fmt.Sprintf("Error unmarshalling response: %s", err.Error()),
-		}}
+	if len(a.extraInfo.DefaultKeywords) > 0 {
+		request = mergeKeywords(request, a.extraInfo.DefaultKeywords)
 	}
 
-	bidResponse := adapters.NewBidderResponseWithBidsCapacity(len(request.Imp))
+	switch a.extraInfo.BlockListMode {
+	case blockListDrop:
+		withoutBlockLists := *request
+		withoutBlockLists.BCat = nil
+		withoutBlockLists.BAdv = nil
+		withoutBlockLists.BApp = nil
+		request = &withoutBlockLists
+	case blockListMerge:
+		request = a.mergeBlockLists(request)
+	}
 
-	for _, seatBid := range bidResp.SeatBid {
-		for i := range seatBid.Bid {
-			bid := &seatBid.Bid[i]
-			
-			bidType, err := getBidType(bid, request.Imp)
-			if err != nil {
-				continue
-			}
+	requestExt, err := parseRequestExt(request)
+	if err != nil {
+		errors = append(errors, &errortypes.BadInput{
+			Message: fmt.Sprintf("Error unmarshalling request.ext: %s", err.Error()),
+		})
+	}
+	baseEndpoint := a.requestEndpoint(request, requestExt)
 
-			bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
-				Bid:     bid,
-				BidType: bidType,
-			})
+	imps := request.Imp
+	if a.extraInfo.SingleSizeBanner {
+		imps = splitBannerFormats(imps)
+	}
+
+	// One request per endpoint (and floor currency), keeping the original imp
+	// order within each
+	var requests []*adapters.RequestData
+	groups := a.groupImps(imps, baseEndpoint, a.sandboxed(request))
+	budget := request.TMax
+	if a.extraInfo.SplitTMax && len(groups) > 1 {
+		budget = request.TMax / int64(len(groups))
+	}
+	for _, group := range groups {
+		outgoing := *request
+		outgoing.Imp = group.imps
+		outgoing.TMax = budget
+		if group.currency != "" {
+			outgoing.Cur = []string{group.currency}
+		}
+
+		reqData, err := a.makeRequestData(&outgoing, requestExt, group.endpoint)
+		if err != nil {
+			return nil, []error{err}
+		}
+		if budget != request.TMax {
+			reqData.Headers.Set(TMaxBudgetHeader, strconv.FormatInt(budget, 10))
+		}
+		requests = append(requests, reqData)
+
+		if group.endpoint == baseEndpoint && !a.sandboxed(request) {
+			requests = append(requests, failoverRequests(reqData, a.extraInfo.FailoverEndpoints)...)
 		}
 	}
 
-	return bidResponse, nil
+	return requests, errors
 }
 
-func getBidType(bid *openrtb2.Bid, imps []openrtb2.Imp) (openrtb_ext.BidType, error) {
-	// Find matching impression
-	for _, imp := range imps {
+// failoverRequests copies a primary request once per failover endpoint. The
+// copies carry a FailoverHeader so the endpoint (or a proxy in front of it)
+// can discard them when the primary succeeded; MakeBids drops any duplicate
+// bids that still come back.
+func failoverRequests(primary *adapters.RequestData, endpoints []string) []*adapters.RequestData {
+	requests := make([]*adapters.RequestData, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		failover := *primary
+		failover.Uri = endpoint
+		failover.Headers = primary.Headers.Clone()
+		failover.Headers.Set(FailoverHeader, primary.Uri)
+		requests = append(requests, &failover)
+	}
+	return requests
+}
+
+// failoverClaims records which response first returned bids for each imp of
+// an auction, so a primary request and its failover copies never both
+// deliver bids for the same imp. Auctions are keyed by the request the core
+// passes to every MakeBids call for them.
+type failoverClaims struct {
+	mu       sync.Mutex
+	auctions map[*openrtb2.BidRequest]*failoverClaim
+}
+
+type failoverClaim struct {
+	expires time.Time
+	imps    map[string]*adapters.RequestData
+}
+
+func newFailoverClaims() *failoverClaims {
+	return &failoverClaims{auctions: make(map[*openrtb2.BidRequest]*failoverClaim)}
+}
+
+// claim keeps the bids for imps no other response of the auction answered
+// first, claiming those imps for requestData, and returns how many it dropped.
+// Claims older than failoverClaimTTL are forgotten.
+func (c *failoverClaims) claim(request *openrtb2.BidRequest, requestData *adapters.RequestData, bids []*adapters.TypedBid, now time.Time) ([]*adapters.TypedBid, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, auction := range c.auctions {
+		if now.After(auction.expires) {
+			delete(c.auctions, key)
+		}
+	}
+	if len(bids) == 0 {
+		return bids, 0
+	}
+
+	auction, ok := c.auctions[request]
+	if !ok {
+		auction = &failoverClaim{expires: now.Add(failoverClaimTTL), imps: make(map[string]*adapters.RequestData)}
+		c.auctions[request] = auction
+	}
+	kept := make([]*adapters.TypedBid, 0, len(bids))
+	for _, typedBid := range bids {
+		owner, claimed := auction.imps[typedBid.Bid.ImpID]
+		if claimed && owner != requestData {
+			continue
+		}
+		auction.imps[typedBid.Bid.ImpID] = requestData
+		kept = append(kept, typedBid)
+	}
+	return kept, len(bids) - len(kept)
+}
+
+// trimImpsByFloor keeps the limit imps with the highest bidfloor, in their
+// original order, and returns the IDs of the imps it dropped
+func trimImpsByFloor(imps []openrtb2.Imp, limit int) ([]openrtb2.Imp, []string) {
+	order := make([]int, len(imps))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(x, y int) int {
+		return cmp.Compare(imps[y].BidFloor, imps[x].BidFloor)
+	})
+
+	keep := make([]bool, len(imps))
+	for _, i := range order[:limit] {
+		keep[i] = true
+	}
+	kept := make([]openrtb2.Imp, 0, limit)
+	var trimmed []string
+	for i, imp := range imps {
+		if keep[i] {
+			kept = append(kept, imp)
+		} else {
+			trimmed = append(trimmed, imp.ID)
+		}
+	}
+	return kept, trimmed
+}
+
+// mergeBlockLists returns a copy of the request with the default bcat, badv
+// and bapp merged into its own
+func (a *adapter) mergeBlockLists(request *openrtb2.BidRequest) *openrtb2.BidRequest {
+	merged := *request
+	merged.BCat = mergeLists(request.BCat, a.extraInfo.DefaultBCat)
+	merged.BAdv = mergeLists(request.BAdv, a.extraInfo.DefaultBAdv)
+	merged.BApp = mergeLists(request.BApp, a.extraInfo.DefaultBApp)
+	return &merged
+}
+
+// mergeLists appends the defaults missing from values, keeping the request's order first
+func mergeLists(values, defaults []string) []string {
+	if len(defaults) == 0 {
+		return values
+	}
+	merged := make([]string, 0, len(values)+len(defaults))
+	merged = append(merged, values...)
+	for _, value := range defaults {
+		if !slices.Contains(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// limitAdTracking returns a copy of the request without device identifiers
+// and, when dropUser is set, without the user. The core's Device is not modified.
+func limitAdTracking(request *openrtb2.BidRequest, dropUser bool) *openrtb2.BidRequest {
+	limited := *request
+	device := *request.Device
+	device.IFA = ""
+	device.DIDSHA1 = ""
+	device.DIDMD5 = ""
+	device.DPIDSHA1 = ""
+	device.DPIDMD5 = ""
+	device.MACSHA1 = ""
+	device.MACMD5 = ""
+	limited.Device = &device
+	if dropUser {
+		limited.User = nil
+	}
+	return &limited
+}
+
+// promoteRegsExt returns a copy of the request with regs.ext.gdpr and
+// regs.ext.us_privacy copied into the native 2.6 fields. Native values win;
+// an unparseable regs.ext leaves the request as it is.
+func promoteRegsExt(request *openrtb2.BidRequest) *openrtb2.BidRequest {
+	var regsExt openrtb_ext.ExtRegs
+	if err := json.Unmarshal(request.Regs.Ext, &regsExt); err != nil {
+		return request
+	}
+	regs := *request.Regs
+	if regs.GDPR == nil {
+		regs.GDPR = regsExt.GDPR
+	}
+	if regs.USPrivacy == "" {
+		regs.USPrivacy = regsExt.USPrivacy
+	}
+	promoted := *request
+	promoted.Regs = &regs
+	return &promoted
+}
+
+// mergeKeywords returns a copy of the request with the defaults added to the
+// comma-separated site or app keywords. The core's Site and App are not modified.
+func mergeKeywords(request *openrtb2.BidRequest, defaults []string) *openrtb2.BidRequest {
+	merged := *request
+	if request.Site != nil {
+		site := *request.Site
+		site.Keywords = mergeKeywordList(site.Keywords, defaults)
+		merged.Site = &site
+	}
+	if request.App != nil {
+		app := *request.App
+		app.Keywords = mergeKeywordList(app.Keywords, defaults)
+		merged.App = &app
+	}
+	return &merged
+}
+
+// mergeKeywordList merges defaults into a comma-separated keyword string
+func mergeKeywordList(keywords string, defaults []string) string {
+	var values []string
+	for _, keyword := range strings.Split(keywords, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			values = append(values, keyword)
+		}
+	}
+	return strings.Join(mergeLists(values, defaults), ",")
+}
+
+// reduceGeoPrecision returns a copy of the request with device and user
+// coordinates rounded. The core's Device, User and Geo objects are not modified.
+func reduceGeoPrecision(request *openrtb2.BidRequest, decimals int) *openrtb2.BidRequest {
+	reduced := *request
+	if request.Device != nil && request.Device.Geo != nil {
+		device := *request.Device
+		device.Geo = roundGeo(request.Device.Geo, decimals)
+		reduced.Device = &device
+	}
+	if request.User != nil && request.User.Geo != nil {
+		user := *request.User
+		user.Geo = roundGeo(request.User.Geo, decimals)
+		reduced.User = &user
+	}
+	return &reduced
+}
+
+func roundGeo(geo *openrtb2.Geo, decimals int) *openrtb2.Geo {
+	rounded := *geo
+	scale := math.Pow10(decimals)
+	if geo.Lat != nil {
+		lat := math.Round(*geo.Lat*scale) / scale
+		rounded.Lat = &lat
+	}
+	if geo.Lon != nil {
+		lon := math.Round(*geo.Lon*scale) / scale
+		rounded.Lon = &lon
+	}
+	return &rounded
+}
+
+// roundFloorUp rounds floor up to decimals places. The small epsilon keeps a
+// floor already at that precision, like 1.1 held as 1.1000000000000001, as is.
+func roundFloorUp(floor float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Ceil(floor*scale-1e-9) / scale
+}
+
+// parseRequestExt decodes request.ext. The result is never nil so callers can
+// read the Prebid fields even when the ext is missing or malformed.
+func parseRequestExt(request *openrtb2.BidRequest) (*openrtb_ext.ExtRequest, error) {
+	var requestExt openrtb_ext.ExtRequest
+	if len(request.Ext) == 0 {
+		return &requestExt, nil
+	}
+	if err := json.Unmarshal(request.Ext, &requestExt); err != nil {
+		return &openrtb_ext.ExtRequest{}, err
+	}
+	return &requestExt, nil
+}
+
+// sandboxed reports whether a request is test traffic for SandboxEndpoint
+func (a *adapter) sandboxed(request *openrtb2.BidRequest) bool {
+	return request.Test == 1 && a.extraInfo.SandboxEndpoint != ""
+}
+
+// requestEndpoint returns the endpoint for imps without a more specific route
+func (a *adapter) requestEndpoint(request *openrtb2.BidRequest, requestExt *openrtb_ext.ExtRequest) string {
+	if a.sandboxed(request) {
+		return a.extraInfo.SandboxEndpoint
+	}
+	channel := requestExt.Prebid.Channel
+	if channel != nil && channel.Name == "amp" && a.extraInfo.AMPEndpoint != "" {
+		return a.extraInfo.AMPEndpoint
+	}
+	if request.Device != nil {
+		if endpoint, ok := a.extraInfo.DeviceTypeEndpoints[request.Device.DeviceType]; ok {
+			return endpoint
+		}
+	}
+	return a.endpoint
+}
+
+// requestTraceID returns request.id, falling back to source.tid
+func requestTraceID(request *openrtb2.BidRequest) string {
+	if request.ID != "" {
+		return request.ID
+	}
+	if request.Source != nil {
+		return request.Source.TID
+	}
+	return ""
+}
+
+// makeRequestData serializes one outgoing request for the given endpoint
+func (a *adapter) makeRequestData(request *openrtb2.BidRequest, requestExt *openrtb_ext.ExtRequest, endpoint string) (*adapters.RequestData, error) {
+	// Serialize request
+	reqJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create HTTP request
+	headers := http.Header{}
+	headers.Add("Content-Type", "application/json;charset=utf-8")
+	if a.extraInfo.Accept != "" {
+		headers.Add("Accept", a.extraInfo.Accept)
+	} else {
+		headers.Add("Accept", "application/json")
+	}
+	if channel := requestExt.Prebid.Channel; channel != nil && channel.Name != "" && a.extraInfo.ChannelHeader != "" {
+		headers.Set(a.extraInfo.ChannelHeader, channel.Name)
+	}
+	if server := requestExt.Prebid.Server; server != nil && a.extraInfo.ServerInfoHeaders {
+		if server.ExternalUrl != "" {
+			headers.Set(ServerURLHeader, server.ExternalUrl)
+		}
+		if server.DataCenter != "" {
+			headers.Set(ServerDataCenterHeader, server.DataCenter)
+		}
+	}
+	if a.extraInfo.AcceptLanguage {
+		if language := requestLanguage(request); language != "" {
+			headers.Set("Accept-Language", language)
+		}
+	}
+	if a.extraInfo.KeepAlive {
+		headers.Set("Connection", "keep-alive")
+	}
+	if a.extraInfo.UserAgent != "" {
+		headers.Set("User-Agent", a.extraInfo.UserAgent)
+	}
+	if a.extraInfo.RequestIDHeader {
+		if id := requestTraceID(request); id != "" {
+			headers.Set(RequestIDHeader, id)
+		}
+	}
+	if a.extraInfo.TMaxHeader != "" && request.TMax > 0 {
+		headers.Set(a.extraInfo.TMaxHeader, strconv.FormatInt(request.TMax, 10))
+	}
+
+	if a.extraInfo.GzipMinBytes > 0 && len(reqJSON) > a.extraInfo.GzipMinBytes {
+		compressed, err := gzipBody(reqJSON)
+		if err != nil {
+			return nil, err
+		}
+		reqJSON = compressed
+		headers.Set("Content-Encoding", "gzip")
+	}
+
+	return &adapters.RequestData{
+		Method:  "POST",
+		Uri:     endpoint,
+		Body:    reqJSON,
+		Headers: headers,
+		ImpIDs:  openrtb_ext.GetImpIDs(request.Imp),
+	}, nil
+}
+
+// requestLanguage returns the device language, or the content language when
+// the device does not set one
+func requestLanguage(request *openrtb2.BidRequest) string {
+	if request.Device != nil && request.Device.Language != "" {
+		return request.Device.Language
+	}
+	if request.Site != nil && request.Site.Content != nil && request.Site.Content.Language != "" {
+		return request.Site.Content.Language
+	}
+	if request.App != nil && request.App.Content != nil && request.App.Content.Language != "" {
+		return request.App.Content.Language
+	}
+	return ""
+}
+
+// gzipBody compresses a request body
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// impGroup is a set of imps sent together to one endpoint, in one floor
+// currency when extraInfo.SplitByFloorCurrency is set
+type impGroup struct {
+	impGroupKey
+	imps []openrtb2.Imp
+}
+
+type impGroupKey struct {
+	endpoint string
+	currency string
+}
+
+// groupImps splits imps by the endpoint each one routes to and, when
+// configured, by floor currency. Sandboxed imps all stay on baseEndpoint.
+func (a *adapter) groupImps(imps []openrtb2.Imp, baseEndpoint string, sandboxed bool) []impGroup {
+	var groups []impGroup
+	groupIndex := make(map[impGroupKey]int)
+
+	for i := range imps {
+		key := impGroupKey{endpoint: baseEndpoint}
+		if !sandboxed {
+			key.endpoint = a.impEndpoint(&imps[i], baseEndpoint)
+		}
+		if a.extraInfo.SplitByFloorCurrency {
+			key.currency = floorCurrency(&imps[i])
+		}
+		index, ok := groupIndex[key]
+		if !ok {
+			index = len(groups)
+			groupIndex[key] = index
+			groups = append(groups, impGroup{impGroupKey: key})
+		}
+		groups[index].imps = append(groups[index].imps, imps[i])
+	}
+	return groups
+}
+
+// floorCurrency returns imp.bidfloorcur, or USD, its OpenRTB default
+func floorCurrency(imp *openrtb2.Imp) string {
+	if imp.BidFloorCur == "" {
+		return "USD"
+	}
+	return imp.BidFloorCur
+}
+
+// impEndpoint returns the endpoint an imp is sent to
+func (a *adapter) impEndpoint(imp *openrtb2.Imp, baseEndpoint string) string {
+	if imp.Rwdd == 1 && a.extraInfo.RewardedEndpoint != "" {
+		return a.extraInfo.RewardedEndpoint
+	}
+	if imp.Instl == 1 && a.extraInfo.InterstitialEndpoint != "" {
+		return a.extraInfo.InterstitialEndpoint
+	}
+	return baseEndpoint
+}
+
+// MakeBids unpacks the server's response into Bids
+func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	status := response.StatusCode
+	if mapped, ok := a.extraInfo.StatusMapping[status]; ok {
+		status = mapped
+	}
+
+	if status == http.StatusNoContent {
+		return nil, nil
+	}
+
+	if status == http.StatusBadRequest {
+		return nil, []error{&errortypes.BadInput{
+			Message: fmt.Sprintf("Bad request: %s", string(response.Body)),
+		}}
+	}
+
+	// MakeBids can't issue a follow-up request, so a redirect the HTTP client
+	// did not follow is reported with its target for the endpoint config
+	if isRedirect(status) {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Endpoint redirected with status %d to %q, update the configured endpoint", response.StatusCode, response.Headers.Get("Location")),
+		}}
+	}
+
+	if status == http.StatusTooManyRequests || status >= http.StatusInternalServerError {
+		cooldown := retryAfter(response.Headers)
+		return nil, []error{&CooldownError{
+			BadServerResponse: errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Unexpected status code: %d, recommended cooldown %s", response.StatusCode, cooldown),
+			},
+			Cooldown: cooldown,
+		}}
+	}
+
+	if status != http.StatusOK {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Unexpected status code: %d", response.StatusCode),
+		}}
+	}
+
+	if a.extraInfo.MaxResponseBytes > 0 && len(response.Body) > a.extraInfo.MaxResponseBytes {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Response body of %d bytes exceeds the %d byte limit", len(response.Body), a.extraInfo.MaxResponseBytes),
+		}}
+	}
+
+	bidResp, prices, errors, err := a.decodeResponse(response)
+	if err != nil {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Error unmarshalling response: %s", err.Error()),
+		}}
+	}
+
+	if reason, ok := noBidReason(&bidResp); ok {
+		errors = append(errors, &errortypes.Warning{
+			Message: fmt.Sprintf("No bids returned: nbr %d (%s)", reason, describeNoBidReason(reason)),
+		})
+	}
+
+	bidResponse := adapters.NewBidderResponseWithBidsCapacity(len(request.Imp))
+	if bidResp.Cur != "" {
+		bidResponse.Currency = bidResp.Cur
+	} else if cur := responseBidExtCurrency(bidResp.SeatBid); cur != "" {
+		bidResponse.Currency = cur
+	}
+
+	mediaTypes := impMediaTypes(request.Imp)
+
+	var splitImpIDs map[string]string
+	if a.extraInfo.SingleSizeBanner {
+		splitImpIDs = splitImpOrigins(requestData.ImpIDs, request.Imp)
+	}
+
+	// MakeRequests already reported a malformed request.ext
+	requestExt, _ := parseRequestExt(request)
+	alternateCodes := requestExt.Prebid.AlternateBidderCodes
+
+	// Bids are held to the block lists the endpoint was sent, defaults included
+	blockLists := request
+	if a.extraInfo.BlockListMode == blockListMerge {
+		blockLists = a.mergeBlockLists(request)
+	}
+
+	for _, seatBid := range bidResp.SeatBid {
+		if len(a.extraInfo.AllowedSeats) > 0 && !slices.Contains(a.extraInfo.AllowedSeats, seatBid.Seat) {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("seat %q is not allowed, dropping %d bids", seatBid.Seat, len(seatBid.Bid)),
+			})
+			continue
+		}
+
+		// a seat the publisher did not allow bids under the adapter's own
+		// code; bidMeta still records it in meta.seat
+		seat, err := a.alternateSeat(seatBid.Seat, alternateCodes)
+		if err != nil {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("seat %q is not an allowed bidder code, returning its %d bids as %s: %s", seatBid.Seat, len(seatBid.Bid), a.bidderName, err.Error()),
+			})
+		}
+
+		seatBids := make([]*adapters.TypedBid, 0, len(seatBid.Bid))
+		seatMeta := seatDealMeta(seatBid.Ext)
+		dropped := 0
+
+		for i := range seatBid.Bid {
+			bid := &seatBid.Bid[i]
+
+			// request is the full auction request, so only trust imps that
+			// were actually sent in this RequestData
+			if len(requestData.ImpIDs) > 0 && !slices.Contains(requestData.ImpIDs, bid.ImpID) {
+				errors = append(errors, &errortypes.BadServerResponse{
+					Message: fmt.Sprintf("bid %s: imp %s was not sent in this request", bid.ID, bid.ImpID),
+				})
+				dropped++
+				continue
+			}
+
+			if originalID, ok := splitImpIDs[bid.ImpID]; ok {
+				bid.ImpID = originalID
+			}
+
+			if bidID := prebidBidID(bid.Ext); bidID != "" {
+				bid.ID = bidID
+			}
+
+			if err := a.checkBid(bid, prices[bid], bidResponse.Currency); err != nil {
+				errors = append(errors, err)
+				dropped++
+				continue
+			}
+
+			if err := checkBlockLists(bid, blockLists); err != nil {
+				errors = append(errors, err)
+				dropped++
+				continue
+			}
+
+			bidType, err := getBidType(bid, request.Imp, a.extraInfo.PreferMType)
+			if err != nil {
+				errors = append(errors, &errortypes.Warning{
+					Message: fmt.Sprintf("bid %s: %s", bid.ID, err.Error()),
+				})
+				dropped++
+				continue
+			}
+
+			if allowed, ok := mediaTypes[bid.ImpID]; ok && !slices.Contains(allowed, string(bidType)) {
+				errors = append(errors, &errortypes.Warning{
+					Message: fmt.Sprintf("bid %s: %s is not an allowed media type for imp %s", bid.ID, bidType, bid.ImpID),
+				})
+				dropped++
+				continue
+			}
+
+			if a.extraInfo.ValidateVAST && bidType == openrtb_ext.BidTypeVideo && bid.AdM != "" {
+				if err := checkVAST(bid.AdM); err != nil {
+					errors = append(errors, &errortypes.Warning{
+						Message: fmt.Sprintf("bid %s: malformed VAST: %s", bid.ID, err.Error()),
+					})
+					dropped++
+					continue
+				}
+			}
+
+			if ext, ok := normalizeBidEvents(bid.Ext); ok {
+				bid.Ext = ext
+			}
+
+			a.translateCategories(bid)
+			meta := bidMeta(bid, seatBid.Seat, seatMeta)
+
+			seatBids = append(seatBids, &adapters.TypedBid{
+				Bid:     bid,
+				BidType: bidType,
+				BidMeta: meta,
+				Seat:    seat,
+			})
+		}
+
+		// seatbid.group=1 means the seat's bids win or lose together
+		if seatBid.Group == 1 && dropped > 0 {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("seat %s: dropping grouped bids because %d of %d were invalid", seatBid.Seat, dropped, len(seatBid.Bid)),
+			})
+			continue
+		}
+		if a.extraInfo.BidMetrics {
+			a.recordSeatBids(seatBid.Seat, seatBids)
+		}
+		bidResponse.Bids = append(bidResponse.Bids, seatBids...)
+	}
+
+	// Before expandDedupedBids, whose copies share their bid's ID
+	if a.extraInfo.DedupBids {
+		var dropped int
+		if bidResponse.Bids, dropped = dedupBidsByID(bidResponse.Bids); dropped > 0 {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("Dropped %d duplicate bids returned under more than one seat", dropped),
+			})
+		}
+	}
+
+	if a.extraInfo.DedupImps {
+		bidResponse.Bids = expandDedupedBids(bidResponse.Bids, request.Imp)
+	}
+
+	if len(a.extraInfo.FailoverEndpoints) > 0 {
+		var dropped int
+		if bidResponse.Bids, dropped = a.failoverClaims.claim(request, requestData, bidResponse.Bids, time.Now()); dropped > 0 {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("Dropped %d bids for imps another primary or failover response already answered", dropped),
+			})
+		}
+	}
+
+	if a.extraInfo.SortDeals {
+		sortDealsFirst(bidResponse.Bids)
+	}
+
+	if passthrough := responsePassthrough(bidResp.Ext); passthrough != nil {
+		for _, typedBid := range bidResponse.Bids {
+			ext, err := withBidPassthrough(typedBid.Bid.Ext, passthrough)
+			if err != nil {
+				errors = append(errors, &errortypes.BadServerResponse{
+					Message: fmt.Sprintf("bid %s: invalid bid.ext: %s", typedBid.Bid.ID, err.Error()),
+				})
+				continue
+			}
+			typedBid.Bid.Ext = ext
+		}
+	}
+
+	// Protected Audience configs answer imps sent with imp.ext.ae=1
+	fledgeConfigs := responseFledgeConfigs(bidResp.Ext)
+	impIDs := make([]string, 0, len(fledgeConfigs))
+	for impID := range fledgeConfigs {
+		impIDs = append(impIDs, impID)
+	}
+	slices.Sort(impIDs)
+	for _, impID := range impIDs {
+		originalID := impID
+		if id, ok := splitImpIDs[impID]; ok {
+			originalID = id
+		}
+		if !slices.ContainsFunc(request.Imp, func(imp openrtb2.Imp) bool { return imp.ID == originalID }) {
+			errors = append(errors, &errortypes.Warning{
+				Message: fmt.Sprintf("dropping fledge auction config for unknown imp %s", impID),
+			})
+			continue
+		}
+		bidResponse.FledgeAuctionConfigs = append(bidResponse.FledgeAuctionConfigs, &openrtb_ext.FledgeAuctionConfig{
+			ImpId:  originalID,
+			Config: fledgeConfigs[impID],
+		})
+	}
+
+	return bidResponse, errors
+}
+
+// alternateSeat returns the seat a seatbid's bids are tagged with. Once the
+// publisher sends request.ext.prebid.alternatebiddercodes, a seat other than
+// the adapter's own must be allowed there; without it, or for a seat that is
+// not allowed, bids are not tagged.
+func (a *adapter) alternateSeat(seat string, codes *openrtb_ext.ExtAlternateBidderCodes) (openrtb_ext.BidderName, error) {
+	if codes == nil || seat == "" || seat == string(a.bidderName) {
+		return "", nil
+	}
+	if ok, err := codes.IsValidBidderCode(string(a.bidderName), seat); !ok {
+		return "", err
+	}
+	return openrtb_ext.BidderName(seat), nil
+}
+
+// recordSeatBids labels a seat's bids with bidder, media type and seat. Bids
+// without a seat are labelled with the bidder name.
+func (a *adapter) recordSeatBids(seat string, bids []*adapters.TypedBid) {
+	if seat == "" {
+		seat = string(a.bidderName)
+	}
+	for _, typedBid := range bids {
+		a.recordBid(map[string]string{
+			BidLabelBidder:    string(a.bidderName),
+			BidLabelMediaType: string(typedBid.BidType),
+			BidLabelSeat:      seat,
+		})
+	}
+}
+
+// translateCategories rewrites bid.cat through the adapter's translator
+func (a *adapter) translateCategories(bid *openrtb2.Bid) {
+	for i, category := range bid.Cat {
+		if translated, ok := a.translateCategory(category); ok {
+			bid.Cat[i] = translated
+		}
+	}
+}
+
+// retryAfter reads a Retry-After header in either delta-seconds or HTTP-date
+// form, falling back to defaultCooldown when it is missing or unparseable
+func retryAfter(headers http.Header) time.Duration {
+	value := headers.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at).Round(time.Second), 0)
+	}
+	return defaultCooldown
+}
+
+// isRedirect reports whether status is a 3xx redirect carrying a Location
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// bidMeta builds the Prebid meta for a bid from its seat's meta, its adomain
+// and cat (see baseBidMeta), the DSP seat it came from and its ext, or nil
+// when none of them carries anything the core reads
+func bidMeta(bid *openrtb2.Bid, seat string, seatMeta *openrtb_ext.ExtBidPrebidMeta) *openrtb_ext.ExtBidPrebidMeta {
+	meta := baseBidMeta(bid)
+	if seatMeta != nil {
+		seatCopy := *seatMeta
+		if meta != nil {
+			seatCopy.AdvertiserDomains = meta.AdvertiserDomains
+			seatCopy.PrimaryCategoryID = meta.PrimaryCategoryID
+			seatCopy.SecondaryCategoryIDs = meta.SecondaryCategoryIDs
+		}
+		meta = &seatCopy
+	}
+	if seat != "" {
+		if meta == nil {
+			meta = &openrtb_ext.ExtBidPrebidMeta{}
+		}
+		meta.Seat = seat
+	}
+
+	if len(bid.Ext) == 0 {
+		return meta
+	}
+	var bidExt struct {
+		DChain json.RawMessage `json:"dchain"`
+	}
+	if err := json.Unmarshal(bid.Ext, &bidExt); err != nil || len(bidExt.DChain) == 0 {
+		return meta
+	}
+	if meta == nil {
+		meta = &openrtb_ext.ExtBidPrebidMeta{}
+	}
+	meta.DChain = bidExt.DChain
+	return meta
+}
+
+// seatDealMeta reads seatbid.ext.deal, whose keys follow the Prebid meta names
+// (e.g. demandSource, networkName), to apply to every bid in the seat. A
+// missing or malformed deal ext gives nil.
+func seatDealMeta(ext json.RawMessage) *openrtb_ext.ExtBidPrebidMeta {
+	if len(ext) == 0 {
+		return nil
+	}
+	var seatExt struct {
+		Deal *openrtb_ext.ExtBidPrebidMeta `json:"deal"`
+	}
+	if err := json.Unmarshal(ext, &seatExt); err != nil {
+		return nil
+	}
+	return seatExt.Deal
+}
+
+// sortDealsFirst stably orders deal bids ahead of open-market bids, by
+// descending deal tier
+func sortDealsFirst(bids []*adapters.TypedBid) {
+	for _, typedBid := range bids {
+		typedBid.DealPriority = bidDealTier(typedBid.Bid)
+	}
+	slices.SortStableFunc(bids, func(x, y *adapters.TypedBid) int {
+		xDeal, yDeal := x.Bid.DealID != "", y.Bid.DealID != ""
+		if xDeal != yDeal {
+			if xDeal {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(y.DealPriority, x.DealPriority)
+	})
+}
+
+// bidDealTier returns bid.ext.dealtier, or 0 when the bid has none
+func bidDealTier(bid *openrtb2.Bid) int {
+	if bid.DealID == "" || len(bid.Ext) == 0 {
+		return 0
+	}
+	var ext struct {
+		DealTier int `json:"dealtier"`
+	}
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil {
+		return 0
+	}
+	return ext.DealTier
+}
+
+// responsePassthrough returns ext.prebid.passthrough from the response, if any
+func responsePassthrough(ext json.RawMessage) json.RawMessage {
+	if len(ext) == 0 {
+		return nil
+	}
+	var responseExt struct {
+		Prebid struct {
+			Passthrough json.RawMessage `json:"passthrough"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(ext, &responseExt); err != nil {
+		return nil
+	}
+	return responseExt.Prebid.Passthrough
+}
+
+// checkVAST reports why adm is not a well-formed XML document rooted at a
+// VAST element. Prefixed and default namespaces are resolved by the decoder.
+func checkVAST(adm string) error {
+	decoder := xml.NewDecoder(strings.NewReader(adm))
+	root := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && root == "" {
+			root = start.Name.Local
+		}
+	}
+	if root != "VAST" {
+		return fmt.Errorf("root element is %q, want VAST", root)
+	}
+	return nil
+}
+
+// noBidReasons names the OpenRTB 2.x no-bid reason codes
+var noBidReasons = map[openrtb3.NoBidReason]string{
+	0:  "unknown error",
+	1:  "technical error",
+	2:  "invalid request",
+	3:  "known web spider",
+	4:  "suspected non-human traffic",
+	5:  "cloud, data center or proxy IP",
+	6:  "unsupported device",
+	7:  "blocked publisher or site",
+	8:  "unmatched user",
+	9:  "daily reader cap met",
+	10: "daily domain cap met",
+}
+
+func describeNoBidReason(reason openrtb3.NoBidReason) string {
+	if description, ok := noBidReasons[reason]; ok {
+		return description
+	}
+	return "endpoint-specific"
+}
+
+// noBidReason returns the reason an endpoint gave for a response without
+// bids, from the top-level nbr or, for endpoints that put it there, ext.nbr
+func noBidReason(bidResp *openrtb2.BidResponse) (openrtb3.NoBidReason, bool) {
+	for _, seatBid := range bidResp.SeatBid {
+		if len(seatBid.Bid) > 0 {
+			return 0, false
+		}
+	}
+	if bidResp.NBR != nil {
+		return *bidResp.NBR, true
+	}
+	var ext struct {
+		NBR *openrtb3.NoBidReason `json:"nbr"`
+	}
+	if len(bidResp.Ext) == 0 || json.Unmarshal(bidResp.Ext, &ext) != nil || ext.NBR == nil {
+		return 0, false
+	}
+	return *ext.NBR, true
+}
+
+// responseFledgeConfigs returns the Protected Audience auction configs the
+// endpoint sends in ext.fledge_auction_configs, keyed by imp ID
+func responseFledgeConfigs(ext json.RawMessage) map[string]json.RawMessage {
+	if len(ext) == 0 {
+		return nil
+	}
+	var responseExt struct {
+		FledgeAuctionConfigs map[string]json.RawMessage `json:"fledge_auction_configs"`
+	}
+	if err := json.Unmarshal(ext, &responseExt); err != nil {
+		return nil
+	}
+	return responseExt.FledgeAuctionConfigs
+}
+
+// prebidBidID returns bid.ext.prebid.bidid, the stable ID some endpoints
+// assign for logging, or "" when the bid doesn't carry one
+func prebidBidID(ext json.RawMessage) string {
+	var bidExt openrtb_ext.ExtBid
+	if len(ext) == 0 || json.Unmarshal(ext, &bidExt) != nil || bidExt.Prebid == nil {
+		return ""
+	}
+	return bidExt.Prebid.BidId
+}
+
+// normalizeBidEvents moves endpoint event URLs from bid.ext.events to
+// bid.ext.prebid.events, where the core reads them. It reports false, leaving
+// ext alone, when there is nothing to move or prebid.events is already set.
+func normalizeBidEvents(ext json.RawMessage) (json.RawMessage, bool) {
+	if len(ext) == 0 {
+		return nil, false
+	}
+	var bidExt map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &bidExt); err != nil {
+		return nil, false
+	}
+	var events openrtb_ext.ExtBidPrebidEvents
+	if raw, ok := bidExt["events"]; !ok || json.Unmarshal(raw, &events) != nil || (events.Win == "" && events.Imp == "") {
+		return nil, false
+	}
+
+	prebid := make(map[string]json.RawMessage)
+	if raw, ok := bidExt["prebid"]; ok {
+		if err := json.Unmarshal(raw, &prebid); err != nil {
+			return nil, false
+		}
+	}
+	if _, ok := prebid["events"]; ok {
+		return nil, false
+	}
+
+	var err error
+	if prebid["events"], err = json.Marshal(events); err != nil {
+		return nil, false
+	}
+	if bidExt["prebid"], err = json.Marshal(prebid); err != nil {
+		return nil, false
+	}
+	delete(bidExt, "events")
+	normalized, err := json.Marshal(bidExt)
+	return normalized, err == nil
+}
+
+// withBidPassthrough sets bid.ext.prebid.passthrough unless the bid already
+// carries its own passthrough value
+func withBidPassthrough(ext, passthrough json.RawMessage) (json.RawMessage, error) {
+	bidExt := make(map[string]json.RawMessage)
+	if len(ext) > 0 {
+		if err := json.Unmarshal(ext, &bidExt); err != nil {
+			return nil, err
+		}
+	}
+
+	prebid := make(map[string]json.RawMessage)
+	if raw, ok := bidExt["prebid"]; ok {
+		if err := json.Unmarshal(raw, &prebid); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := prebid["passthrough"]; ok {
+		return ext, nil
+	}
+	prebid["passthrough"] = passthrough
+
+	raw, err := json.Marshal(prebid)
+	if err != nil {
+		return nil, err
+	}
+	bidExt["prebid"] = raw
+	return json.Marshal(bidExt)
+}
+
+// dedupImps drops imps that are identical to an earlier imp apart from their ID.
+// It returns the kept imps and, for each kept imp ID, the IDs collapsed into it.
+func dedupImps(imps []openrtb2.Imp) ([]openrtb2.Imp, map[string][]string) {
+	unique := make([]openrtb2.Imp, 0, len(imps))
+	duplicates := make(map[string][]string)
+	keptByKey := make(map[string]string, len(imps))
+
+	for _, imp := range imps {
+		keyed := imp
+		keyed.ID = ""
+		key, err := json.Marshal(keyed)
+		if err != nil {
+			unique = append(unique, imp)
+			continue
+		}
+
+		keptID, seen := keptByKey[string(key)]
+		if !seen {
+			keptByKey[string(key)] = imp.ID
+			unique = append(unique, imp)
+			continue
+		}
+		if imp.ID != keptID {
+			duplicates[keptID] = append(duplicates[keptID], imp.ID)
+		}
+	}
+	return unique, duplicates
+}
+
+// splitBannerFormats replaces each banner-only imp that has several formats
+// with one imp per format, identified by splitImpIDSeparator and the format index
+func splitBannerFormats(imps []openrtb2.Imp) []openrtb2.Imp {
+	split := make([]openrtb2.Imp, 0, len(imps))
+
+	for _, imp := range imps {
+		if imp.Banner == nil || imp.Video != nil || imp.Audio != nil || imp.Native != nil || len(imp.Banner.Format) < 2 {
+			split = append(split, imp)
+			continue
+		}
+
+		for i, format := range imp.Banner.Format {
+			banner := *imp.Banner
+			banner.Format = []openrtb2.Format{format}
+			w, h := format.W, format.H
+			banner.W, banner.H = &w, &h
+
+			sized := imp
+			sized.ID = imp.ID + splitImpIDSeparator + strconv.Itoa(i)
+			sized.Banner = &banner
+			split = append(split, sized)
+		}
+	}
+	return split
+}
+
+// splitImpOrigins maps each imp ID sent in a request that splitBannerFormats
+// created back to the imp it was split from. It reads the IDs that were sent
+// rather than re-splitting request.Imp, whose imps MakeRequests transformed
+// before splitting.
+func splitImpOrigins(sentIDs []string, imps []openrtb2.Imp) map[string]string {
+	originalIDs := make(map[string]string)
+	for _, id := range sentIDs {
+		i := strings.LastIndex(id, splitImpIDSeparator)
+		if i < 0 || slices.ContainsFunc(imps, func(imp openrtb2.Imp) bool { return imp.ID == id }) {
+			continue
+		}
+		originalIDs[id] = id[:i]
+	}
+	return originalIDs
+}
+
+// dedupBidsByID keeps the highest-priced bid for each bid ID, in the position
+// the ID first appeared, and returns how many bids it dropped
+func dedupBidsByID(bids []*adapters.TypedBid) ([]*adapters.TypedBid, int) {
+	positions := make(map[string]int, len(bids))
+	deduped := make([]*adapters.TypedBid, 0, len(bids))
+	for _, typedBid := range bids {
+		i, seen := positions[typedBid.Bid.ID]
+		if !seen {
+			positions[typedBid.Bid.ID] = len(deduped)
+			deduped = append(deduped, typedBid)
+			continue
+		}
+		if typedBid.Bid.Price > deduped[i].Bid.Price {
+			deduped[i] = typedBid
+		}
+	}
+	return deduped, len(bids) - len(deduped)
+}
+
+// expandDedupedBids copies each bid onto the imps that dedupImps collapsed into its imp
+func expandDedupedBids(bids []*adapters.TypedBid, imps []openrtb2.Imp) []*adapters.TypedBid {
+	_, duplicates := dedupImps(imps)
+	if len(duplicates) == 0 {
+		return bids
+	}
+
+	expanded := make([]*adapters.TypedBid, 0, len(bids))
+	for _, typedBid := range bids {
+		expanded = append(expanded, typedBid)
+		for _, impID := range duplicates[typedBid.Bid.ImpID] {
+			bid := *typedBid.Bid
+			bid.ImpID = impID
+			copied := *typedBid
+			copied.Bid = &bid
+			expanded = append(expanded, &copied)
+		}
+	}
+	return expanded
+}
+
+// impMediaTypes returns the mediaTypes param of each imp that restricts them
+func impMediaTypes(imps []openrtb2.Imp) map[string][]string {
+	mediaTypes := make(map[string][]string)
+	for i := range imps {
+		var bidderExt adapters.ExtImpBidder
+		if err := json.Unmarshal(imps[i].Ext, &bidderExt); err != nil {
+			continue
+		}
+		var impExt openrtb_ext.ExtImp{{NAME}}
+		if err := decodeParams(bidderExt.Bidder, &impExt); err != nil {
+			continue
+		}
+		if len(impExt.MediaTypes) > 0 {
+			mediaTypes[imps[i].ID] = impExt.MediaTypes
+		}
+	}
+	return mediaTypes
+}
+
+// checkBid returns an error when a bid must be dropped from the response.
+// exactPrice is the bid's decimal price text when the decoder kept it.
+func (a *adapter) checkBid(bid *openrtb2.Bid, exactPrice, currency string) error {
+	if a.extraInfo.EnforceResponseCurrency {
+		if bidCur := bidExtCurrency(bid); bidCur != "" && bidCur != currency {
+			return &errortypes.Warning{
+				Message: fmt.Sprintf("bid %s: currency %s does not match response currency %s", bid.ID, bidCur, currency),
+			}
+		}
+	}
+
+	for _, re := range a.blockedAdmPatterns {
+		if re.MatchString(bid.AdM) {
+			return &errortypes.Warning{
+				Message: fmt.Sprintf("bid %s: adm matches blocked pattern %q", bid.ID, re.String()),
+			}
+		}
+	}
+
+	if a.extraInfo.MinBidPrice > 0 && priceBelow(bid.Price, exactPrice, a.extraInfo.MinBidPrice) {
+		price := strconv.FormatFloat(bid.Price, 'f', -1, 64)
+		if exactPrice != "" {
+			price = exactPrice
+		}
+		return &errortypes.Warning{
+			Message: fmt.Sprintf("bid %s: price %s %s is below the minimum of %v", bid.ID, price, currency, a.extraInfo.MinBidPrice),
+		}
+	}
+
+	if a.extraInfo.MaxAdmBytes > 0 && len(bid.AdM) > a.extraInfo.MaxAdmBytes {
+		return &errortypes.Warning{
+			Message: fmt.Sprintf("bid %s: adm is %d bytes, over the limit of %d", bid.ID, len(bid.AdM), a.extraInfo.MaxAdmBytes),
+		}
+	}
+	return nil
+}
+
+// priceBelow reports whether a bid price is below minimum. A known decimal
+// price text is compared exactly with the minimum's shortest decimal form, so
+// a price that only rounds up to the minimum as a float64 is still below it.
+// Exponent forms and texts over maxExactPriceLen are compared as float64.
+func priceBelow(price float64, exactPrice string, minimum float64) bool {
+	if exactPrice == "" || len(exactPrice) > maxExactPriceLen || strings.ContainsAny(exactPrice, "eE") {
+		return price < minimum
+	}
+	exact, ok := new(big.Rat).SetString(exactPrice)
+	if !ok {
+		return price < minimum
+	}
+	floor, _ := new(big.Rat).SetString(strconv.FormatFloat(minimum, 'f', -1, 64))
+	return exact.Cmp(floor) < 0
+}
+
+// responseBidExtCurrency returns the first bid.ext.cur in the response, for
+// endpoints that leave the top-level cur empty
+func responseBidExtCurrency(seatBids []openrtb2.SeatBid) string {
+	for _, seatBid := range seatBids {
+		for i := range seatBid.Bid {
+			if cur := bidExtCurrency(&seatBid.Bid[i]); cur != "" {
+				return cur
+			}
+		}
+	}
+	return ""
+}
+
+// checkBlockLists warns about a bid whose adomain is in request.badv or whose
+// category is in request.bcat, in case the endpoint ignored the block lists
+func checkBlockLists(bid *openrtb2.Bid, request *openrtb2.BidRequest) error {
+	for _, domain := range bid.ADomain {
+		if slices.ContainsFunc(request.BAdv, func(blocked string) bool { return strings.EqualFold(blocked, domain) }) {
+			return &errortypes.Warning{
+				Message: fmt.Sprintf("bid %s: adomain %s is blocked by request.badv", bid.ID, domain),
+			}
+		}
+	}
+	for _, category := range bid.Cat {
+		if slices.Contains(request.BCat, category) {
+			return &errortypes.Warning{
+				Message: fmt.Sprintf("bid %s: category %s is blocked by request.bcat", bid.ID, category),
+			}
+		}
+	}
+	return nil
+}
+
+// bidExtCurrency returns the per-bid currency some endpoints set in bid.ext.cur
+func bidExtCurrency(bid *openrtb2.Bid) string {
+	if len(bid.Ext) == 0 {
+		return ""
+	}
+	var ext struct {
+		Cur string `json:"cur"`
+	}
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil {
+		return ""
+	}
+	return ext.Cur
+}
+
+// decodeResponse picks the decoder registered for the response Content-Type,
+// falling back to decodeBidResponse for JSON and unlabelled bodies
+func (a *adapter) decodeResponse(response *adapters.ResponseData) (openrtb2.BidResponse, bidPrices, []error, error) {
+	if mediaType, _, err := mime.ParseMediaType(response.Headers.Get("Content-Type")); err == nil {
+		if decode, ok := a.responseDecoders[mediaType]; ok {
+			bidResp, err := decode(response.Body)
+			return bidResp, nil, nil, err
+		}
+	}
+	return a.decodeBidResponse(response.Body)
+}
+
+// decodeBidResponse unmarshals the response body according to the extra info
+// settings. Per-bid problems are returned as errors alongside the response.
+func (a *adapter) decodeBidResponse(body []byte) (openrtb2.BidResponse, bidPrices, []error, error) {
+	if a.extraInfo.NativeAdmObject {
+		normalized, err := normalizeNativeAdm(body)
+		if err != nil {
+			return openrtb2.BidResponse{}, nil, nil, err
+		}
+		body = normalized
+	}
+
+	if a.extraInfo.PreciseBidPrice {
+		return decodePreciseBidResponse(body)
+	}
+
+	var bidResp openrtb2.BidResponse
+	if err := json.Unmarshal(body, &bidResp); err != nil {
+		return openrtb2.BidResponse{}, nil, nil, err
+	}
+	return bidResp, nil, nil, nil
+}
+
+// normalizeNativeAdm rewrites any bid.adm sent as a JSON object into its
+// string form. Bids whose adm is already a string are left untouched.
+func normalizeNativeAdm(body []byte) ([]byte, error) {
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	var seatBids []map[string]json.RawMessage
+	if raw, ok := resp["seatbid"]; ok {
+		if err := json.Unmarshal(raw, &seatBids); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, seatBid := range seatBids {
+		var bids []map[string]json.RawMessage
+		if raw, ok := seatBid["bid"]; ok {
+			if err := json.Unmarshal(raw, &bids); err != nil {
+				return nil, err
+			}
+		}
+		for _, bid := range bids {
+			adm := bytes.TrimSpace(bid["adm"])
+			if len(adm) == 0 || adm[0] != '{' {
+				continue
+			}
+			encoded, err := json.Marshal(string(adm))
+			if err != nil {
+				return nil, err
+			}
+			bid["adm"] = encoded
+		}
+		raw, err := json.Marshal(bids)
+		if err != nil {
+			return nil, err
+		}
+		seatBid["bid"] = raw
+	}
+
+	if len(seatBids) > 0 {
+		raw, err := json.Marshal(seatBids)
+		if err != nil {
+			return nil, err
+		}
+		resp["seatbid"] = raw
+	}
+	return json.Marshal(resp)
+}
+
+// preciseBid shadows openrtb2.Bid.Price so the endpoint's decimal text is kept
+type preciseBid struct {
+	openrtb2.Bid
+	Price json.Number `json:"price"`
+}
+
+type preciseSeatBid struct {
+	openrtb2.SeatBid
+	Bid []preciseBid `json:"bid"`
+}
+
+type preciseBidResponse struct {
+	openrtb2.BidResponse
+	SeatBid []preciseSeatBid `json:"seatbid,omitempty"`
+}
+
+// bidPrices maps each decoded bid to its price as the endpoint wrote it
+type bidPrices map[*openrtb2.Bid]string
+
+// decodePreciseBidResponse decodes each price as a json.Number, which takes
+// both numbers and numeric strings, and keeps its text in the returned
+// bidPrices. A price that does not parse as a float64 drops only that bid.
+func decodePreciseBidResponse(body []byte) (openrtb2.BidResponse, bidPrices, []error, error) {
+	var precise preciseBidResponse
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&precise); err != nil {
+		return openrtb2.BidResponse{}, nil, nil, err
+	}
+
+	var errors []error
+	prices := make(bidPrices)
+	bidResp := precise.BidResponse
+	bidResp.SeatBid = make([]openrtb2.SeatBid, 0, len(precise.SeatBid))
+	for _, preciseSeat := range precise.SeatBid {
+		seatBid := preciseSeat.SeatBid
+		seatBid.Bid = make([]openrtb2.Bid, 0, len(preciseSeat.Bid))
+		texts := make([]string, 0, len(preciseSeat.Bid))
+		for _, pb := range preciseSeat.Bid {
+			price, err := strconv.ParseFloat(pb.Price.String(), 64)
+			if err != nil {
+				errors = append(errors, &errortypes.BadServerResponse{
+					Message: fmt.Sprintf("bid %s: invalid price %q", pb.ID, pb.Price.String()),
+				})
+				continue
+			}
+			bid := pb.Bid
+			bid.Price = price
+			seatBid.Bid = append(seatBid.Bid, bid)
+			texts = append(texts, pb.Price.String())
+		}
+		// MakeBids walks the same backing array, so the pointers stay valid
+		for i := range seatBid.Bid {
+			prices[&seatBid.Bid[i]] = texts[i]
+		}
+		bidResp.SeatBid = append(bidResp.SeatBid, seatBid)
+	}
+	return bidResp, prices, errors, nil
+}
+
+// dropDeprecatedImpFields clears video.placement when plcmt is set and
+// video.protocol when protocols is set. The video object is copied first.
+func dropDeprecatedImpFields(imp *openrtb2.Imp) {
+	if imp.Video == nil {
+		return
+	}
+	video := *imp.Video
+	if video.Plcmt != 0 {
+		video.Placement = 0
+	}
+	if len(video.Protocols) > 0 {
+		video.Protocol = 0
+	}
+	imp.Video = &video
+}
+
+// dropDeprecatedUserFields returns a copy of the request with user.yob and
+// user.gender cleared
+func dropDeprecatedUserFields(request *openrtb2.BidRequest) *openrtb2.BidRequest {
+	user := *request.User
+	user.Yob = 0
+	user.Gender = ""
+	withoutDeprecated := *request
+	withoutDeprecated.User = &user
+	return &withoutDeprecated
+}
+
+// withImpTID returns imp.ext with a generated tid when it has none
+func withImpTID(ext json.RawMessage, newTID func() (string, error)) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &fields); err != nil {
+		return nil, err
+	}
+	var tid string
+	if err := json.Unmarshal(fields["tid"], &tid); err == nil && tid != "" {
+		return ext, nil
+	}
+	tid, err := newTID()
+	if err != nil {
+		return nil, err
+	}
+	fields["tid"], _ = json.Marshal(tid)
+	return json.Marshal(fields)
+}
+
+// randomTID returns a random (version 4) UUID
+func randomTID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// stripUnsupportedMediaTypes removes the imp's media objects not in
+// supported and reports whether any media object is left
+func stripUnsupportedMediaTypes(imp *openrtb2.Imp, supported []string) bool {
+	if !slices.Contains(supported, string(openrtb_ext.BidTypeBanner)) {
+		imp.Banner = nil
+	}
+	if !slices.Contains(supported, string(openrtb_ext.BidTypeVideo)) {
+		imp.Video = nil
+	}
+	if !slices.Contains(supported, string(openrtb_ext.BidTypeAudio)) {
+		imp.Audio = nil
+	}
+	if !slices.Contains(supported, string(openrtb_ext.BidTypeNative)) {
+		imp.Native = nil
+	}
+	return imp.Banner != nil || imp.Video != nil || imp.Audio != nil || imp.Native != nil
+}
+
+// stripImpExtPrebid returns a copy of imp.ext without the prebid object.
+// The bidder params and any other keys are kept as-is.
+func stripImpExtPrebid(ext json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "prebid")
+	return json.Marshal(fields)
+}
+
+// validateVideo checks a video imp against the OpenRTB 2.6 recommended fields.
+// Missing fields are reported as warnings; the imp is still sent.
+func validateVideo(imp *openrtb2.Imp) []error {
+	var warnings []error
+
+	if len(imp.Video.Protocols) == 0 && imp.Video.Protocol == 0 {
+		warnings = append(warnings, &errortypes.Warning{
+			Message: fmt.Sprintf("imp %s: video.protocols is recommended", imp.ID),
+		})
+	}
+
+	if imp.Video.Plcmt == 0 && imp.Video.Placement == 0 {
+		warnings = append(warnings, &errortypes.Warning{
+			Message: fmt.Sprintf("imp %s: video.plcmt is recommended", imp.ID),
+		})
+	}
+
+	return warnings
+}
+
+// validateAudio checks an audio imp the way validateVideo does. feed,
+// companiontype and companionad are forwarded untouched.
+func validateAudio(imp *openrtb2.Imp) []error {
+	if len(imp.Audio.Protocols) == 0 {
+		return []error{&errortypes.Warning{
+			Message: fmt.Sprintf("imp %s: audio.protocols is recommended", imp.ID),
+		}}
+	}
+	return nil
+}
+
+// filterAudioProtocols keeps only the supported protocols on a copy of
+// imp.audio and reports whether any remain. An imp that lists no protocols
+// is left alone for validateAudio to flag.
+func filterAudioProtocols(imp *openrtb2.Imp, supported []adcom1.MediaCreativeSubtype) bool {
+	if len(imp.Audio.Protocols) == 0 {
+		return true
+	}
+	audio := *imp.Audio
+	audio.Protocols = slices.DeleteFunc(slices.Clone(audio.Protocols), func(protocol adcom1.MediaCreativeSubtype) bool {
+		return !slices.Contains(supported, protocol)
+	})
+	imp.Audio = &audio
+	return len(audio.Protocols) > 0
+}
+
+// validateContent warns about required fields missing from site.content or
+// app.content, or about the content object itself being absent
+func validateContent(request *openrtb2.BidRequest, required []string) []error {
+	var content *openrtb2.Content
+	switch {
+	case request.App != nil:
+		content = request.App.Content
+	case request.Site != nil:
+		content = request.Site.Content
+	}
+	if content == nil {
+		return []error{&errortypes.Warning{Message: "site/app.content is missing"}}
+	}
+
+	var missing []string
+	for _, field := range required {
+		if !contentFields[field](content) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []error{&errortypes.Warning{
+		Message: fmt.Sprintf("content is missing required fields %v", missing),
+	}}
+}
+
+// validateSKAdN warns when imp.ext.skadn lacks a version, sourceapp or
+// skadnetids. Imps without a skadn block are not checked.
+func validateSKAdN(imp *openrtb2.Imp) []error {
+	var ext struct {
+		SKAdN *struct {
+			Version    string   `json:"version"`
+			Versions   []string `json:"versions"`
+			SourceApp  string   `json:"sourceapp"`
+			SKAdNetIDs []string `json:"skadnetids"`
+		} `json:"skadn"`
+	}
+	if err := json.Unmarshal(imp.Ext, &ext); err != nil || ext.SKAdN == nil {
+		return nil
+	}
+
+	var missing []string
+	if ext.SKAdN.Version == "" && len(ext.SKAdN.Versions) == 0 {
+		missing = append(missing, "versions")
+	}
+	if ext.SKAdN.SourceApp == "" {
+		missing = append(missing, "sourceapp")
+	}
+	if len(ext.SKAdN.SKAdNetIDs) == 0 {
+		missing = append(missing, "skadnetids")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []error{&errortypes.Warning{
+		Message: fmt.Sprintf("imp %s: imp.ext.skadn is missing %v", imp.ID, missing),
+	}}
+}
+
+// validateQty rejects a DOOH imp whose qty.multiplier can't be used to price
+// the screen's audience. Imps without qty are sent as a single impression.
+func validateQty(imp *openrtb2.Imp) error {
+	if imp.Qty == nil {
+		return nil
+	}
+	if m := imp.Qty.Multiplier; m <= 0 || math.IsNaN(m) || math.IsInf(m, 0) {
+		return &errortypes.BadInput{
+			Message: fmt.Sprintf("imp %s: qty.multiplier must be a positive number, got %v", imp.ID, m),
+		}
+	}
+	return nil
+}
+
+// validateMetrics warns about imp.metric entries the endpoint can't act on:
+// ones without a type, and viewability, a probability, outside 0-1
+func validateMetrics(imp *openrtb2.Imp) []error {
+	var warnings []error
+	for i, metric := range imp.Metric {
+		switch {
+		case metric.Type == "":
+			warnings = append(warnings, &errortypes.Warning{
+				Message: fmt.Sprintf("imp %s: metric %d has no type", imp.ID, i),
+			})
+		case metric.Type == "viewability" && (metric.Value < 0 || metric.Value > 1):
+			warnings = append(warnings, &errortypes.Warning{
+				Message: fmt.Sprintf("imp %s: viewability metric %v is outside 0-1", imp.ID, metric.Value),
+			})
+		}
+	}
+	return warnings
+}
+
+// validatePosition warns when a banner or video imp leaves pos unknown
+func validatePosition(imp *openrtb2.Imp) []error {
+	var warnings []error
+
+	if imp.Banner != nil && (imp.Banner.Pos == nil || *imp.Banner.Pos == adcom1.PositionUnknown) {
+		warnings = append(warnings, &errortypes.Warning{
+			Message: fmt.Sprintf("imp %s: banner.pos is unknown", imp.ID),
+		})
+	}
+
+	if imp.Video != nil && (imp.Video.Pos == nil || *imp.Video.Pos == adcom1.PositionUnknown) {
+		warnings = append(warnings, &errortypes.Warning{
+			Message: fmt.Sprintf("imp %s: video.pos is unknown", imp.ID),
+		})
+	}
+
+	return warnings
+}
+
+func getBidType(bid *openrtb2.Bid, imps []openrtb2.Imp, preferMType bool) (openrtb_ext.BidType, error) {
+	if preferMType {
+		switch bid.MType {
+		case openrtb2.MarkupBanner:
+			return openrtb_ext.BidTypeBanner, nil
+		case openrtb2.MarkupVideo:
+			return openrtb_ext.BidTypeVideo, nil
+		case openrtb2.MarkupAudio:
+			return openrtb_ext.BidTypeAudio, nil
+		case openrtb2.MarkupNative:
+			return openrtb_ext.BidTypeNative, nil
+		}
+	}
+
+	// Find matching impression
+	for _, imp := range imps {
 		if imp.ID == bid.ImpID {
 			if imp.Banner != nil {
 				return openrtb_ext.BidTypeBanner, nil
 			}
 			if imp.Video != nil {
+				if isCompanionBid(bid, imp.Video) {
+					return openrtb_ext.BidTypeBanner, nil
+				}
 				return openrtb_ext.BidTypeVideo, nil
 			}
+			if imp.Audio != nil {
+				return openrtb_ext.BidTypeAudio, nil
+			}
 			if imp.Native != nil {
 				return openrtb_ext.BidTypeNative, nil
 			}
@@ -142,3 +2485,27 @@ func getBidType(bid *openrtb2.Bid, imps []openrtb2.Imp) (openrtb_ext.BidType, er
 	}
 	return "", fmt.Errorf("could not determine bid type for imp %s", bid.ImpID)
 }
+
+// isCompanionBid reports whether a bid on a video imp is one of its companion
+// banners rather than the video itself: either bid.ext.companion is set, or
+// the markup is not VAST and the size matches a requested companion.
+func isCompanionBid(bid *openrtb2.Bid, video *openrtb2.Video) bool {
+	if len(bid.Ext) > 0 {
+		var ext struct {
+			Companion bool `json:"companion"`
+		}
+		if err := json.Unmarshal(bid.Ext, &ext); err == nil && ext.Companion {
+			return true
+		}
+	}
+
+	if bid.AdM == "" || strings.Contains(bid.AdM, "<VAST") {
+		return false
+	}
+	for _, companion := range video.CompanionAd {
+		if companion.W != nil && companion.H != nil && *companion.W == bid.W && *companion.H == bid.H {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package {{NAME_LOWER}}
+
+import (
+	"net/http"
+	"testing"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+)
+
+// FuzzMakeBids feeds arbitrary response bodies to MakeBids, which must never panic.
+// Run with: go test -fuzz=FuzzMakeBids
+func FuzzMakeBids(f *testing.F) {
+	f.Add([]byte(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1.5}]}]}`))
+	f.Add([]byte(`{"seatbid":[{"bid":[{"impid":"imp-1","price":"1e400","adm":{"native":{}}}]}]}`))
+	f.Add([]byte(`{"seatbid":null}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(``))
+
+	bidders := []adapters.Bidder{
+		newTestBidder(f, ""),
+		newTestBidder(f, `{"preciseBidPrice":true,"nativeAdmObject":true,"dedupImps":true}`),
+	}
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}},
+			{ID: "imp-3", Native: &openrtb2.Native{Request: "{}"}},
+		},
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		for _, bidder := range bidders {
+			bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Body:       body,
+			})
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package {{NAME_LOWER}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the testdata golden files")
+
+// TestMakeRequestsGolden compares the outgoing body for a fixed request with
+// testdata/request.golden.json, catching unintended serialization changes.
+// Run go test -run TestMakeRequestsGolden -update after an intended change.
+func TestMakeRequestsGolden(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		TMax: 500,
+		Cur:  []string{"USD"},
+		Imp: []openrtb2.Imp{{
+			ID:          "imp-1",
+			Banner:      &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 300, H: 600}}},
+			BidFloor:    0.5,
+			BidFloorCur: "USD",
+			Ext:         json.RawMessage(`{"bidder":{"placementId":"123"}}`),
+		}},
+		Site: &openrtb2.Site{
+			Page:      "https://example.com/article",
+			Publisher: &openrtb2.Publisher{ID: "publisher-1"},
+		},
+		Device: &openrtb2.Device{UA: "Mozilla/5.0", IP: "192.0.2.1", Language: "en"},
+		User:   &openrtb2.User{BuyerUID: "buyer-1"},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 || len(reqs) != 1 {
+		t.Fatalf("MakeRequests: %d requests, errors %v", len(reqs), errs)
+	}
+
+	var got bytes.Buffer
+	if err := json.Indent(&got, reqs[0].Body, "", "  "); err != nil {
+		t.Fatalf("indenting request body: %v", err)
+	}
+	got.WriteByte('\n')
+
+	golden := filepath.Join("testdata", "request.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", golden, err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading %s (run with -update to create it): %v", golden, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("request body differs from %s; run with -update if the change is intended\ngot:\n%s\nwant:\n%s", golden, got.String(), want)
+	}
+}
@@ -0,0 +1,94 @@
+package {{NAME_LOWER}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// TestIntegrationMockServer runs the full MakeRequests -> HTTP -> MakeBids
+// loop against a mock endpoint, covering the transport the JSON samples skip.
+func TestIntegrationMockServer(t *testing.T) {
+	var received openrtb2.BidRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.Method != http.MethodPost {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"test-request","cur":"USD","seatbid":[{"seat":"{{NAME_LOWER}}","bid":[
+			{"id":"bid-1","impid":"imp-1","price":1.5,"adm":"<div>ad</div>","crid":"creative-1","w":300,"h":250,"mtype":1}
+		]}]}`))
+	}))
+	defer server.Close()
+
+	bidder, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: server.URL},
+		config.Server{},
+	)
+	if buildErr != nil {
+		t.Fatalf("Builder returned unexpected error: %v", buildErr)
+	}
+
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{
+			ID:     "imp-1",
+			Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+			Ext:    []byte(`{"bidder":{"placementId":"123"}}`),
+		}},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 || len(reqs) != 1 {
+		t.Fatalf("MakeRequests: %d requests, errors %v", len(reqs), errs)
+	}
+
+	httpReq, err := http.NewRequest(reqs[0].Method, reqs[0].Uri, bytes.NewReader(reqs[0].Body))
+	if err != nil {
+		t.Fatalf("building HTTP request: %v", err)
+	}
+	httpReq.Header = reqs[0].Headers
+	httpResp, err := server.Client().Do(httpReq)
+	if err != nil {
+		t.Fatalf("calling mock server: %v", err)
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		t.Fatalf("reading mock response: %v", err)
+	}
+
+	if received.ID != request.ID || len(received.Imp) != 1 {
+		t.Errorf("mock server received %+v", received)
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], &adapters.ResponseData{
+		StatusCode: httpResp.StatusCode,
+		Body:       respBody,
+		Headers:    httpResp.Header,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("MakeBids errors: %v", errs)
+	}
+	if bidResponse.Currency != "USD" || len(bidResponse.Bids) != 1 {
+		t.Fatalf("bid response = %+v", bidResponse)
+	}
+	if typedBid := bidResponse.Bids[0]; typedBid.Bid.ID != "bid-1" || typedBid.BidType != openrtb_ext.BidTypeBanner {
+		t.Errorf("bid = %+v, type %s", typedBid.Bid, typedBid.BidType)
+	}
+}
@@ -0,0 +1,26 @@
+package {{NAME_LOWER}}
+
+import (
+	"testing"
+
+	"{{PBS_MODULE}}/adapters/adapterstest"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+func TestJsonSamples(t *testing.T) {
+	bidder, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{
+			Endpoint:         "https://example.com/bid",
+			ExtraAdapterInfo: `{"sandboxEndpoint":"https://sandbox.example.com/bid"}`,
+		},
+		config.Server{},
+	)
+
+	if buildErr != nil {
+		t.Fatalf("Builder returned unexpected error: %v", buildErr)
+	}
+
+	adapterstest.RunJSONBidderTest(t, "{{NAME_LOWER}}", bidder)
+}
@@ -0,0 +1,114 @@
+package {{NAME_LOWER}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// Table-driven alternative to the JSON samples; add a case per behavior.
+
+func TestMakeRequestsTable(t *testing.T) {
+	tests := []struct {
+		name         string
+		imps         []openrtb2.Imp
+		wantRequests int
+		wantErrors   int
+	}{
+		{
+			name:         "valid imp",
+			imps:         []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":"123"}}`)}},
+			wantRequests: 1,
+		},
+		{
+			name:       "invalid imp ext",
+			imps:       []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":"bad"}`)}},
+			wantErrors: 1,
+		},
+		{
+			name:       "no imps",
+			wantErrors: 1,
+		},
+	}
+
+	bidder := newTestBidder(t, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{ID: "test-request", Imp: tt.imps}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(reqs) != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", len(reqs), tt.wantRequests)
+			}
+			if len(errs) != tt.wantErrors {
+				t.Errorf("errors = %v, want %d", errs, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestMakeBidsTable(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantBids   []openrtb_ext.BidType
+		wantErrors int
+	}{
+		{
+			name:     "banner bid",
+			status:   http.StatusOK,
+			body:     `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1}]}]}`,
+			wantBids: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner},
+		},
+		{
+			name:   "no content",
+			status: http.StatusNoContent,
+		},
+		{
+			name:       "server error",
+			status:     http.StatusInternalServerError,
+			wantErrors: 1,
+		},
+		{
+			name:       "malformed body",
+			status:     http.StatusOK,
+			body:       `{`,
+			wantErrors: 1,
+		},
+	}
+
+	bidder := newTestBidder(t, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: tt.status, Body: []byte(tt.body)})
+			if len(errs) != tt.wantErrors {
+				t.Errorf("errors = %v, want %d", errs, tt.wantErrors)
+			}
+
+			var got []openrtb_ext.BidType
+			if bidResponse != nil {
+				for _, typedBid := range bidResponse.Bids {
+					got = append(got, typedBid.BidType)
+				}
+			}
+			if len(got) != len(tt.wantBids) {
+				t.Fatalf("bids = %v, want %v", got, tt.wantBids)
+			}
+			for i := range got {
+				if got[i] != tt.wantBids[i] {
+					t.Errorf("bid %d type = %s, want %s", i, got[i], tt.wantBids[i])
+				}
+			}
+		})
+	}
+}
@@ -1,23 +1,3679 @@
 package {{NAME_LOWER}}
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/prebid/prebid-server/v2/adapters/adapterstest"
-	"github.com/prebid/prebid-server/v2/config"
-	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"{{OPENRTB_MODULE}}/adcom1"
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/currency"
+	"{{PBS_MODULE}}/errortypes"
+	"{{PBS_MODULE}}/openrtb_ext"
 )
 
-func TestJsonSamples(t *testing.T) {
+// newTestBidder builds the adapter with the given ExtraAdapterInfo JSON.
+func newTestBidder(t testing.TB, extraInfo string) adapters.Bidder {
+	t.Helper()
+
 	bidder, buildErr := Builder(
 		openrtb_ext.Bidder{{NAME}},
-		config.Adapter{Endpoint: "https://example.com/bid"},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: extraInfo},
 		config.Server{},
 	)
-
 	if buildErr != nil {
 		t.Fatalf("Builder returned unexpected error: %v", buildErr)
 	}
+	return bidder
+}
+
+func TestBuilderInvalidExtraInfo(t *testing.T) {
+	_, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: "{"},
+		config.Server{},
+	)
+	if buildErr == nil {
+		t.Fatal("expected an error for malformed extra info")
+	}
+}
+
+func TestMakeRequestsVideoValidation(t *testing.T) {
+	bidder := newTestBidder(t, "")
+
+	tests := []struct {
+		name         string
+		video        *openrtb2.Video
+		wantWarnings int
+	}{
+		{
+			name: "compliant",
+			video: &openrtb2.Video{
+				MIMEs:     []string{"video/mp4"},
+				Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30},
+				Plcmt:     adcom1.VideoPlcmtInstream,
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:         "minimal",
+			video:        &openrtb2.Video{MIMEs: []string{"video/mp4"}},
+			wantWarnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID: "test-request",
+				Imp: []openrtb2.Imp{{
+					ID:    "imp-1",
+					Video: tt.video,
+					Ext:   json.RawMessage(`{"bidder":{"placementId":"123"}}`),
+				}},
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(reqs) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(reqs))
+			}
+			if len(errs) != tt.wantWarnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tt.wantWarnings, len(errs), errs)
+			}
+			for _, err := range errs {
+				if _, ok := err.(*errortypes.Warning); !ok {
+					t.Errorf("expected *errortypes.Warning, got %T", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeRequestsStripImpExtPrebid(t *testing.T) {
+	tests := []struct {
+		name       string
+		extraInfo  string
+		wantPrebid bool
+	}{
+		{name: "default keeps prebid", extraInfo: "", wantPrebid: true},
+		{name: "strip enabled", extraInfo: `{"stripImpExtPrebid":true}`, wantPrebid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidder := newTestBidder(t, tt.extraInfo)
+			request := &openrtb2.BidRequest{
+				ID: "test-request",
+				Imp: []openrtb2.Imp{{
+					ID:     "imp-1",
+					Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+					Ext:    json.RawMessage(`{"prebid":{"is_rewarded_inventory":1},"bidder":{"placementId":"123"}}`),
+				}},
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			var ext map[string]json.RawMessage
+			if err := json.Unmarshal(sent.Imp[0].Ext, &ext); err != nil {
+				t.Fatalf("failed to decode imp.ext: %v", err)
+			}
+			if _, ok := ext["prebid"]; ok != tt.wantPrebid {
+				t.Errorf("imp.ext.prebid present = %v, want %v", ok, tt.wantPrebid)
+			}
+			if string(ext["bidder"]) != `{"placementId":"123"}` {
+				t.Errorf("bidder params changed: %s", ext["bidder"])
+			}
+		})
+	}
+}
+
+func TestMakeBidsPreciseBidPrice(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1.234567890123456789},
+			{"id":"bid-2","impid":"imp-1","price":"2.50"},
+			{"id":"bid-3","impid":"imp-1","price":1e400}
+		]}]}`),
+	}
+
+	// Without the option the quoted and overflowing prices fail the response
+	if _, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response); len(errs) != 1 {
+		t.Fatalf("expected the strict decoder to fail, got %v", errs)
+	}
+
+	bidResponse, errs := newTestBidder(t, `{"preciseBidPrice":true}`).MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the overflowing price, got %d: %v", len(errs), errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadServerResponse); !ok || !strings.Contains(errs[0].Error(), "bid-3") {
+		t.Errorf("expected a BadServerResponse naming bid-3, got %T %v", errs[0], errs[0])
+	}
+	if len(bidResponse.Bids) != 2 {
+		t.Fatalf("expected 2 bids, got %d", len(bidResponse.Bids))
+	}
+
+	want, _ := strconv.ParseFloat("1.234567890123456789", 64)
+	if got := bidResponse.Bids[0].Bid.Price; got != want {
+		t.Errorf("price = %v, want %v", got, want)
+	}
+	if got := bidResponse.Bids[1].Bid.Price; got != 2.5 {
+		t.Errorf("string price = %v, want 2.5", got)
+	}
+}
+
+func TestMakeBidsPreciseMinBidPrice(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	// All three prices are the same float64 as the minimum, but only bid-1 is
+	// below it as a decimal
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1.23456789012345669},
+			{"id":"bid-2","impid":"imp-1","price":1.2345678901234567},
+			{"id":"bid-3","impid":"imp-1","price":1.234567890123456789}
+		]}]}`),
+	}
+
+	tests := []struct {
+		name        string
+		extraInfo   string
+		wantBidIDs  []string
+		wantDropped string
+	}{
+		{name: "float comparison", extraInfo: `{"minBidPrice":1.2345678901234567}`, wantBidIDs: []string{"bid-1", "bid-2", "bid-3"}},
+		{name: "decimal comparison", extraInfo: `{"minBidPrice":1.2345678901234567,"preciseBidPrice":true}`, wantBidIDs: []string{"bid-2", "bid-3"}, wantDropped: "1.23456789012345669"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, response)
+			if tt.wantDropped == "" && len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if tt.wantDropped != "" && (len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantDropped)) {
+				t.Fatalf("expected 1 warning naming price %s, got %v", tt.wantDropped, errs)
+			}
+			var got []string
+			for _, typedBid := range bidResponse.Bids {
+				got = append(got, typedBid.Bid.ID)
+			}
+			if !slices.Equal(got, tt.wantBidIDs) {
+				t.Errorf("bids = %v, want %v", got, tt.wantBidIDs)
+			}
+		})
+	}
+}
+
+func TestDedupImps(t *testing.T) {
+	bidder := newTestBidder(t, `{"dedupImps":true}`)
+	banner := &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}
+	ext := json.RawMessage(`{"bidder":{"placementId":"123"}}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: banner, Ext: ext},
+			{ID: "imp-2", Banner: banner, Ext: ext},
+			{ID: "imp-3", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 728, H: 90}}}, Ext: ext},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 2 || sent.Imp[0].ID != "imp-1" || sent.Imp[1].ID != "imp-3" {
+		t.Fatalf("expected imps [imp-1 imp-3], got %v", openrtb_ext.GetImpIDs(sent.Imp))
+	}
+
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1.5}]}]}`),
+	}
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 2 {
+		t.Fatalf("expected the bid to be copied onto imp-2, got %d bids", len(bidResponse.Bids))
+	}
+	if got := bidResponse.Bids[1].Bid.ImpID; got != "imp-2" {
+		t.Errorf("copied bid ImpID = %q, want imp-2", got)
+	}
+	if bidResponse.Bids[0].Bid.ImpID != "imp-1" {
+		t.Errorf("original bid ImpID changed to %q", bidResponse.Bids[0].Bid.ImpID)
+	}
+}
+
+// TestDedupImpsTransformed checks that imps transformed after dedup still
+// get exactly one bid each: generated tids make identical imps differ on the
+// wire, and stripping imp.ext.prebid makes differing imps identical.
+func TestDedupImpsTransformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		imps      []openrtb2.Imp
+		wantSent  []string
+	}{
+		{
+			name:      "generateTids",
+			extraInfo: `{"dedupImps":true,"generateTids":true}`,
+			imps: []openrtb2.Imp{
+				{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+				{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			},
+			wantSent: []string{"imp-1"},
+		},
+		{
+			name:      "stripImpExtPrebid",
+			extraInfo: `{"dedupImps":true,"stripImpExtPrebid":true}`,
+			imps: []openrtb2.Imp{
+				{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{},"prebid":{"is_rewarded_inventory":1}}`)},
+				{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			},
+			wantSent: []string{"imp-1", "imp-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidder := newTestBidder(t, tt.extraInfo)
+			request := &openrtb2.BidRequest{ID: "test-request", Imp: tt.imps}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !slices.Equal(reqs[0].ImpIDs, tt.wantSent) {
+				t.Fatalf("sent imps %v, want %v", reqs[0].ImpIDs, tt.wantSent)
+			}
+
+			// The endpoint bids once on every imp it was sent
+			var bids []string
+			for i, impID := range reqs[0].ImpIDs {
+				bids = append(bids, fmt.Sprintf(`{"id":"bid-%d","impid":%q,"price":1}`, i+1, impID))
+			}
+			response := &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"id":"test-request","seatbid":[{"bid":[` + strings.Join(bids, ",") + `]}]}`),
+			}
+			bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			perImp := make(map[string]int)
+			for _, typedBid := range bidResponse.Bids {
+				perImp[typedBid.Bid.ImpID]++
+			}
+			if want := map[string]int{"imp-1": 1, "imp-2": 1}; !maps.Equal(perImp, want) {
+				t.Errorf("bids per imp = %v, want %v", perImp, want)
+			}
+		})
+	}
+}
+
+func TestBuilderRegionEndpoint(t *testing.T) {
+	extraInfo := `{"regionEndpoints":{"eu":"https://eu.example.com/bid"}}`
+	tests := []struct {
+		dataCenter string
+		want       string
+	}{
+		{dataCenter: "eu", want: "https://eu.example.com/bid"},
+		{dataCenter: "us-east", want: "https://example.com/bid"},
+		{dataCenter: "", want: "https://example.com/bid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataCenter, func(t *testing.T) {
+			bidder, buildErr := Builder(
+				openrtb_ext.Bidder{{NAME}},
+				config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: extraInfo},
+				config.Server{DataCenter: tt.dataCenter},
+			)
+			if buildErr != nil {
+				t.Fatalf("Builder returned unexpected error: %v", buildErr)
+			}
+
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+			reqs, _ := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if reqs[0].Uri != tt.want {
+				t.Errorf("Uri = %q, want %q", reqs[0].Uri, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeBidsNativeAdmObject(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Native: &openrtb2.Native{Request: "{}"}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1,"adm":{"native":{"ver":"1.2"}}}]}]}`),
+	}
+
+	if _, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response); len(errs) == 0 {
+		t.Fatal("expected object-form adm to fail without nativeAdmObject")
+	}
+
+	bidResponse, errs := newTestBidder(t, `{"nativeAdmObject":true}`).MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 1 {
+		t.Fatalf("expected 1 bid, got %d", len(bidResponse.Bids))
+	}
+	if got := bidResponse.Bids[0].Bid.AdM; got != `{"native":{"ver":"1.2"}}` {
+		t.Errorf("AdM = %s", got)
+	}
+	if bidResponse.Bids[0].BidType != openrtb_ext.BidTypeNative {
+		t.Errorf("BidType = %s, want native", bidResponse.Bids[0].BidType)
+	}
+}
+
+func TestMakeRequestsRewardedRouting(t *testing.T) {
+	bidder := newTestBidder(t, `{"rewardedEndpoint":"https://example.com/rewarded"}`)
+	video := &openrtb2.Video{
+		MIMEs:     []string{"video/mp4"},
+		Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30},
+		Plcmt:     adcom1.VideoPlcmtInterstitial,
+	}
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":"123"}}`)},
+			{ID: "imp-2", Video: video, Rwdd: 1, Ext: json.RawMessage(`{"bidder":{"placementId":"456"}}`)},
+			{ID: "imp-3", Video: video, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":1},"bidder":{"placementId":"789"}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Uri != "https://example.com/bid" || len(reqs[0].ImpIDs) != 1 || reqs[0].ImpIDs[0] != "imp-1" {
+		t.Errorf("default request = %s %v", reqs[0].Uri, reqs[0].ImpIDs)
+	}
+	if reqs[1].Uri != "https://example.com/rewarded" || len(reqs[1].ImpIDs) != 2 {
+		t.Errorf("rewarded request = %s %v", reqs[1].Uri, reqs[1].ImpIDs)
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[1].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if sent.Imp[1].Rwdd != 1 {
+		t.Errorf("is_rewarded_inventory not forwarded as imp.rwdd")
+	}
+}
+
+func TestMakeBidsEnforceResponseCurrency(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","cur":"EUR","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"ext":{"cur":"EUR"}},
+			{"id":"bid-2","impid":"imp-1","price":1,"ext":{"cur":"USD"}},
+			{"id":"bid-3","impid":"imp-1","price":1}
+		]}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 || len(bidResponse.Bids) != 3 {
+		t.Fatalf("default should keep all bids, got %d bids and errors %v", len(bidResponse.Bids), errs)
+	}
+	if bidResponse.Currency != "EUR" {
+		t.Errorf("Currency = %s, want EUR", bidResponse.Currency)
+	}
+
+	bidResponse, errs = newTestBidder(t, `{"enforceResponseCurrency":true}`).MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok {
+		t.Errorf("expected a Warning for the mismatched currency, got %T", errs[0])
+	}
+	if len(bidResponse.Bids) != 2 || bidResponse.Bids[1].Bid.ID != "bid-3" {
+		t.Errorf("expected bid-2 to be dropped, got %d bids", len(bidResponse.Bids))
+	}
+}
+
+func TestMakeRequestsTMaxHeader(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		TMax: 450,
+		Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	reqs, _ := newTestBidder(t, "").MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if got := reqs[0].Headers.Get("X-Tmax"); got != "" {
+		t.Errorf("unexpected tmax header %q without tmaxHeader", got)
+	}
+
+	reqs, _ = newTestBidder(t, `{"tmaxHeader":"X-Tmax"}`).MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if got := reqs[0].Headers.Get("X-Tmax"); got != "450" {
+		t.Errorf("X-Tmax = %q, want 450", got)
+	}
+}
+
+func TestMakeBidsAllowedMediaTypes(t *testing.T) {
+	bidder := newTestBidder(t, `{"preferMtype":true}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{
+			ID:     "imp-1",
+			Banner: &openrtb2.Banner{},
+			Video:  &openrtb2.Video{MIMEs: []string{"video/mp4"}},
+			Ext:    json.RawMessage(`{"bidder":{"placementId":"123","mediaTypes":["banner"]}}`),
+		}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"mtype":1},
+			{"id":"bid-2","impid":"imp-1","price":1,"mtype":2}
+		]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-1" {
+		t.Fatalf("expected only the banner bid, got %d bids", len(bidResponse.Bids))
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok {
+		t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+	}
+}
+
+func TestMakeBidsPreferMType(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-banner", Banner: &openrtb2.Banner{}},
+			{ID: "imp-multi", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}},
+		},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-banner","price":1,"mtype":2},
+			{"id":"bid-2","impid":"imp-multi","price":1,"mtype":2},
+			{"id":"bid-3","impid":"imp-multi","price":1}
+		]}]}`),
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantTypes []openrtb_ext.BidType
+	}{
+		{
+			name:      "typed from the imp by default",
+			wantTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeBanner},
+		},
+		{
+			name:      "mtype wins, even against the imp",
+			extraInfo: `{"preferMtype":true}`,
+			wantTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeBanner},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, response)
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var types []openrtb_ext.BidType
+			for _, typedBid := range bidResponse.Bids {
+				types = append(types, typedBid.BidType)
+			}
+			if !slices.Equal(types, tt.wantTypes) {
+				t.Errorf("bid types = %v, want %v", types, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestMakeBidsImpMediaTypeFallback(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-banner", Banner: &openrtb2.Banner{}},
+			{ID: "imp-video", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}},
+			{ID: "imp-audio", Audio: &openrtb2.Audio{MIMEs: []string{"audio/mp4"}}},
+			{ID: "imp-native", Native: &openrtb2.Native{Request: "{}"}},
+		},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-banner","price":1},
+			{"id":"bid-2","impid":"imp-video","price":1},
+			{"id":"bid-3","impid":"imp-audio","price":1},
+			{"id":"bid-4","impid":"imp-native","price":1}
+		]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeAudio, openrtb_ext.BidTypeNative}
+	if len(bidResponse.Bids) != len(want) {
+		t.Fatalf("expected %d bids, got %d", len(want), len(bidResponse.Bids))
+	}
+	for i, typedBid := range bidResponse.Bids {
+		if typedBid.BidType != want[i] {
+			t.Errorf("bid %s type = %s, want %s", typedBid.Bid.ID, typedBid.BidType, want[i])
+		}
+	}
+}
+
+func TestMakeRequestsGzipThreshold(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+	plain, _ := newTestBidder(t, "").MakeRequests(request, &adapters.ExtraRequestInfo{})
+	size := len(plain[0].Body)
+
+	tests := []struct {
+		name         string
+		gzipMinBytes int
+		wantGzip     bool
+	}{
+		{name: "at threshold", gzipMinBytes: size, wantGzip: false},
+		{name: "above threshold", gzipMinBytes: size - 1, wantGzip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidder := newTestBidder(t, fmt.Sprintf(`{"gzipMinBytes":%d}`, tt.gzipMinBytes))
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			gzipped := reqs[0].Headers.Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.wantGzip)
+			}
+			if !gzipped {
+				return
+			}
+
+			reader, err := gzip.NewReader(bytes.NewReader(reqs[0].Body))
+			if err != nil {
+				t.Fatalf("body is not gzip: %v", err)
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("failed to read gzip body: %v", err)
+			}
+			if !bytes.Equal(body, plain[0].Body) {
+				t.Errorf("decompressed body differs from the plain body")
+			}
+		})
+	}
+}
+
+func TestMakeRequestsImpExp(t *testing.T) {
+	bidder := newTestBidder(t, `{"defaultImpExp":300}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Exp: 60, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if sent.Imp[0].Exp != 60 {
+		t.Errorf("preserved exp = %d, want 60", sent.Imp[0].Exp)
+	}
+	if sent.Imp[1].Exp != 300 {
+		t.Errorf("defaulted exp = %d, want 300", sent.Imp[1].Exp)
+	}
+}
+
+func TestMakeRequestsFailoverEndpoints(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	reqs, _ := newTestBidder(t, "").MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request without failover, got %d", len(reqs))
+	}
+
+	bidder := newTestBidder(t, `{"failoverEndpoints":["https://backup.example.com/bid"]}`)
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected primary and failover requests, got %d", len(reqs))
+	}
+	if reqs[0].Uri != "https://example.com/bid" || reqs[0].Headers.Get(FailoverHeader) != "" {
+		t.Errorf("primary request = %s, failover header %q", reqs[0].Uri, reqs[0].Headers.Get(FailoverHeader))
+	}
+	if reqs[1].Uri != "https://backup.example.com/bid" {
+		t.Errorf("failover Uri = %s", reqs[1].Uri)
+	}
+	if got := reqs[1].Headers.Get(FailoverHeader); got != "https://example.com/bid" {
+		t.Errorf("%s = %q, want the primary endpoint", FailoverHeader, got)
+	}
+	if !bytes.Equal(reqs[0].Body, reqs[1].Body) {
+		t.Errorf("failover body differs from the primary body")
+	}
+}
+
+func TestMakeBidsFailoverDuplicates(t *testing.T) {
+	body := []byte(`{"id":"test-request","cur":"USD","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1.5,"adm":"<div></div>"}]}]}`)
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		reverse   bool
+		wantBids  []int
+		wantWarns []int
+	}{
+		{name: "both answer", statuses: []int{http.StatusOK, http.StatusOK}, wantBids: []int{1, 0}, wantWarns: []int{0, 1}},
+		{name: "failover answers first", statuses: []int{http.StatusOK, http.StatusOK}, reverse: true, wantBids: []int{1, 0}, wantWarns: []int{0, 1}},
+		{name: "primary fails", statuses: []int{http.StatusInternalServerError, http.StatusOK}, wantBids: []int{0, 1}, wantWarns: []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+			bidder := newTestBidder(t, `{"failoverEndpoints":["https://backup.example.com/bid"]}`)
+			reqs, _ := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(reqs) != 2 {
+				t.Fatalf("expected primary and failover requests, got %d", len(reqs))
+			}
+			if tt.reverse {
+				reqs[0], reqs[1] = reqs[1], reqs[0]
+			}
+
+			for i, reqData := range reqs {
+				bidResponse, errs := bidder.MakeBids(request, reqData, &adapters.ResponseData{StatusCode: tt.statuses[i], Body: body})
+				bids := 0
+				if bidResponse != nil {
+					bids = len(bidResponse.Bids)
+				}
+				if bids != tt.wantBids[i] {
+					t.Errorf("response %d: %d bids, want %d", i, bids, tt.wantBids[i])
+				}
+				warns := 0
+				for _, err := range errs {
+					if _, ok := err.(*errortypes.Warning); ok {
+						warns++
+					}
+				}
+				if warns != tt.wantWarns[i] {
+					t.Errorf("response %d: %d warnings %v, want %d", i, warns, errs, tt.wantWarns[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFailoverClaimsExpire(t *testing.T) {
+	claims := newFailoverClaims()
+	request := &openrtb2.BidRequest{ID: "test-request"}
+	bids := []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1"}}}
+	now := time.Now()
+
+	if _, dropped := claims.claim(request, &adapters.RequestData{}, bids, now); dropped != 0 {
+		t.Fatalf("first claim dropped %d bids", dropped)
+	}
+	if _, dropped := claims.claim(request, &adapters.RequestData{}, bids, now); dropped != 1 {
+		t.Errorf("second response kept a bid for a claimed imp")
+	}
+	claims.claim(&openrtb2.BidRequest{}, &adapters.RequestData{}, nil, now.Add(failoverClaimTTL+time.Second))
+	if len(claims.auctions) != 0 {
+		t.Errorf("expired claims kept: %d", len(claims.auctions))
+	}
+}
+
+func TestMakeRequestsSandboxEndpoint(t *testing.T) {
+	bidder := newTestBidder(t, `{
+		"sandboxEndpoint":"https://sandbox.example.com/bid",
+		"rewardedEndpoint":"https://rewarded.example.com/bid",
+		"failoverEndpoints":["https://backup.example.com/bid"]
+	}`)
+
+	tests := []struct {
+		name     string
+		test     int8
+		wantUris []string
+	}{
+		{
+			name:     "live traffic",
+			wantUris: []string{"https://example.com/bid", "https://backup.example.com/bid", "https://rewarded.example.com/bid"},
+		},
+		{name: "test traffic", test: 1, wantUris: []string{"https://sandbox.example.com/bid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:   "test-request",
+				Test: tt.test,
+				Imp: []openrtb2.Imp{
+					{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+					{ID: "imp-2", Banner: &openrtb2.Banner{}, Rwdd: 1, Ext: json.RawMessage(`{"bidder":{}}`)},
+				},
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var uris []string
+			for _, req := range reqs {
+				uris = append(uris, req.Uri)
+			}
+			if !slices.Equal(uris, tt.wantUris) {
+				t.Errorf("request URIs = %v, want %v", uris, tt.wantUris)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsGeoPrecision(t *testing.T) {
+	lat, lon := 51.507351, -0.127758
+	request := &openrtb2.BidRequest{
+		ID:     "test-request",
+		Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Lat: &lat, Lon: &lon}},
+		User:   &openrtb2.User{Geo: &openrtb2.Geo{Lat: &lat, Lon: &lon}},
+	}
+
+	reqs, errs := newTestBidder(t, `{"geoPrecision":2}`).MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+
+	for name, geo := range map[string]*openrtb2.Geo{"device": sent.Device.Geo, "user": sent.User.Geo} {
+		if *geo.Lat != 51.51 || *geo.Lon != -0.13 {
+			t.Errorf("%s geo = %v,%v, want 51.51,-0.13", name, *geo.Lat, *geo.Lon)
+		}
+	}
+	if *request.Device.Geo.Lat != lat {
+		t.Errorf("original device geo was modified")
+	}
+}
+
+func TestMakeRequestsBlockListMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantBCat  []string
+		wantBAdv  []string
+		wantBApp  []string
+	}{
+		{
+			name:     "forward by default",
+			wantBCat: []string{"IAB25"},
+			wantBAdv: []string{"blocked.com"},
+			wantBApp: []string{"com.blocked.app"},
+		},
+		{
+			name:      "drop",
+			extraInfo: `{"blockListMode":"drop","defaultBAdv":["default.com"]}`,
+		},
+		{
+			name:      "merge",
+			extraInfo: `{"blockListMode":"merge","defaultBAdv":["default.com","blocked.com"]}`,
+			wantBCat:  []string{"IAB25"},
+			wantBAdv:  []string{"blocked.com", "default.com"},
+			wantBApp:  []string{"com.blocked.app"},
+		},
+		{
+			name:      "merge bapp",
+			extraInfo: `{"blockListMode":"merge","defaultBApp":["com.default.app","com.blocked.app"]}`,
+			wantBCat:  []string{"IAB25"},
+			wantBAdv:  []string{"blocked.com"},
+			wantBApp:  []string{"com.blocked.app", "com.default.app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:   "test-request",
+				Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				BCat: []string{"IAB25"},
+				BAdv: []string{"blocked.com"},
+				BApp: []string{"com.blocked.app"},
+				App:  &openrtb2.App{Bundle: "com.example.app"},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if !slices.Equal(sent.BCat, tt.wantBCat) {
+				t.Errorf("bcat = %v, want %v", sent.BCat, tt.wantBCat)
+			}
+			if !slices.Equal(sent.BAdv, tt.wantBAdv) {
+				t.Errorf("badv = %v, want %v", sent.BAdv, tt.wantBAdv)
+			}
+			if !slices.Equal(sent.BApp, tt.wantBApp) {
+				t.Errorf("bapp = %v, want %v", sent.BApp, tt.wantBApp)
+			}
+		})
+	}
+}
+
+func TestBuilderInvalidBlockListMode(t *testing.T) {
+	_, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: `{"blockListMode":"replace"}`},
+		config.Server{},
+	)
+	if buildErr == nil {
+		t.Fatal("expected an error for an unknown blockListMode")
+	}
+}
+
+func TestMakeBidsSeatBidGroup(t *testing.T) {
+	// bid-1 declares video on a banner-only placement, so it is invalid
+	bidder := newTestBidder(t, `{"preferMtype":true}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"mediaTypes":["banner"]}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		group    int
+		wantBids int
+	}{
+		{name: "ungrouped keeps valid bids", group: 0, wantBids: 1},
+		{name: "grouped drops the whole seat", group: 1, wantBids: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Body: []byte(fmt.Sprintf(`{"id":"test-request","seatbid":[{"seat":"seat-1","group":%d,"bid":[
+					{"id":"bid-1","impid":"imp-1","price":1,"mtype":2},
+					{"id":"bid-2","impid":"imp-2","price":1,"mtype":1}
+				]}]}`, tt.group)),
+			}
+
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+			if len(bidResponse.Bids) != tt.wantBids {
+				t.Errorf("expected %d bids, got %d", tt.wantBids, len(bidResponse.Bids))
+			}
+			if len(errs) != 1+tt.group {
+				t.Errorf("expected %d warnings, got %v", 1+tt.group, errs)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsPosition(t *testing.T) {
+	aboveFold := adcom1.PositionAFold
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{Pos: &aboveFold}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := newTestBidder(t, "").MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors without strictPosition: %v", errs)
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if sent.Imp[0].Banner.Pos == nil || *sent.Imp[0].Banner.Pos != adcom1.PositionAFold {
+		t.Errorf("banner.pos was not preserved")
+	}
+
+	_, errs = newTestBidder(t, `{"strictPosition":true}`).MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning for imp-2, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok {
+		t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+	}
+}
+
+func TestMakeBidsBlockedAdmPatterns(t *testing.T) {
+	bidder := newTestBidder(t, `{"blockedAdmPatterns":["tracker\\.example\\.com"]}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"adm":"<div>ad</div>"},
+			{"id":"bid-2","impid":"imp-1","price":1,"adm":"<img src=\"https://tracker.example.com/px.gif\">"}
+		]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-1" {
+		t.Fatalf("expected only bid-1, got %d bids", len(bidResponse.Bids))
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok {
+		t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+	}
+}
+
+func TestBuilderInvalidBlockedAdmPattern(t *testing.T) {
+	_, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: `{"blockedAdmPatterns":["("]}`},
+		config.Server{},
+	)
+	if buildErr == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+}
 
-	adapterstest.RunJSONBidderTest(t, "{{NAME_LOWER}}", bidder)
+func TestMakeRequestsChannelRouting(t *testing.T) {
+	bidder := newTestBidder(t, `{"channelHeader":"X-Prebid-Channel","ampEndpoint":"https://example.com/amp"}`)
+
+	tests := []struct {
+		name    string
+		ext     string
+		wantUri string
+	}{
+		{name: "amp", ext: `{"prebid":{"channel":{"name":"amp","version":"1.0"}}}`, wantUri: "https://example.com/amp"},
+		{name: "pbjs", ext: `{"prebid":{"channel":{"name":"pbjs","version":"8.0"}}}`, wantUri: "https://example.com/bid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				Ext: json.RawMessage(tt.ext),
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if reqs[0].Uri != tt.wantUri {
+				t.Errorf("Uri = %s, want %s", reqs[0].Uri, tt.wantUri)
+			}
+			if got := reqs[0].Headers.Get("X-Prebid-Channel"); got != tt.name {
+				t.Errorf("X-Prebid-Channel = %q, want %q", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsInvalidDurations(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	video := &openrtb2.Video{
+		MIMEs:     []string{"video/mp4"},
+		Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30},
+		Plcmt:     adcom1.VideoPlcmtInstream,
+	}
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Video: video, Ext: json.RawMessage(`{"bidder":{"placementId":"123","minDuration":5,"maxDuration":30}}`)},
+			{ID: "imp-2", Video: video, Ext: json.RawMessage(`{"bidder":{"placementId":"456","minDuration":30,"maxDuration":5}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadInput); !ok {
+		t.Errorf("expected *errortypes.BadInput, got %T", errs[0])
+	}
+	if len(reqs) != 1 || !slices.Equal(reqs[0].ImpIDs, []string{"imp-1"}) {
+		t.Fatalf("expected only imp-1 to be sent, got %v", reqs)
+	}
+
+	request.Imp = request.Imp[1:]
+	reqs, errs = bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(reqs) != 0 || len(errs) != 1 {
+		t.Errorf("expected no requests and 1 error when every imp is invalid, got %d requests, %v", len(reqs), errs)
+	}
+}
+
+func TestSingleSizeBanner(t *testing.T) {
+	bidder := newTestBidder(t, `{"singleSizeBanner":true}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{
+			ID:     "imp-1",
+			Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}},
+			Ext:    json.RawMessage(`{"bidder":{"placementId":"123"}}`),
+		}},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 2 {
+		t.Fatalf("expected 2 imps, got %d", len(sent.Imp))
+	}
+	for i, want := range []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}} {
+		banner := sent.Imp[i].Banner
+		if len(banner.Format) != 1 || banner.Format[0].W != want.W || banner.Format[0].H != want.H || *banner.W != want.W || *banner.H != want.H {
+			t.Errorf("imp %d banner = %+v, want only %+v", i, banner, want)
+		}
+	}
+
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(fmt.Sprintf(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":%q,"price":1,"w":728,"h":90}]}]}`, sent.Imp[1].ID)),
+	}
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ImpID != "imp-1" {
+		t.Errorf("expected the bid to map back to imp-1, got %+v", bidResponse.Bids)
+	}
+}
+
+// TestSingleSizeBannerStrippedVideo checks split imps map back when the imp
+// was only split after supportedMediaTypes stripped its video
+func TestSingleSizeBannerStrippedVideo(t *testing.T) {
+	bidder := newTestBidder(t, `{"singleSizeBanner":true,"supportedMediaTypes":["banner"]}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{
+			ID:     "imp-1",
+			Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}},
+			Video:  &openrtb2.Video{MIMEs: []string{"video/mp4"}},
+			Ext:    json.RawMessage(`{"bidder":{"placementId":"123"}}`),
+		}},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs[0].ImpIDs) != 2 {
+		t.Fatalf("expected the imp to be split in two, sent %v", reqs[0].ImpIDs)
+	}
+
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(fmt.Sprintf(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":%q,"price":1,"mtype":1}]}]}`, reqs[0].ImpIDs[0])),
+	}
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ImpID != "imp-1" {
+		t.Errorf("expected the bid to map back to imp-1, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestPassthroughRoundTrip(t *testing.T) {
+	bidder := newTestBidder(t, `{"stripImpExtPrebid":true}`)
+	impExt := `{"prebid":{"passthrough":{"slot":"top"}},"bidder":{"placementId":"123"}}`
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(impExt)}},
+		Ext: json.RawMessage(`{"prebid":{"passthrough":{"page":"home"}}}`),
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if string(request.Imp[0].Ext) != impExt {
+		t.Errorf("the core's imp.ext was modified: %s", request.Imp[0].Ext)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if string(sent.Ext) != `{"prebid":{"passthrough":{"page":"home"}}}` {
+		t.Errorf("request.ext.prebid.passthrough not forwarded: %s", sent.Ext)
+	}
+
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","ext":{"prebid":{"passthrough":{"page":"home"}}},"seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1},
+			{"id":"bid-2","impid":"imp-1","price":1,"ext":{"prebid":{"passthrough":{"own":true}}}}
+		]}]}`),
+	}
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if got := string(bidResponse.Bids[0].Bid.Ext); got != `{"prebid":{"passthrough":{"page":"home"}}}` {
+		t.Errorf("bid-1 ext = %s", got)
+	}
+	if got := string(bidResponse.Bids[1].Bid.Ext); got != `{"prebid":{"passthrough":{"own":true}}}` {
+		t.Errorf("bid-2 ext = %s", got)
+	}
+}
+
+func TestMakeRequestsAcceptLanguage(t *testing.T) {
+	bidder := newTestBidder(t, `{"acceptLanguage":true}`)
+	tests := []struct {
+		name   string
+		device *openrtb2.Device
+		site   *openrtb2.Site
+		want   string
+	}{
+		{name: "device language", device: &openrtb2.Device{Language: "de"}, site: &openrtb2.Site{Content: &openrtb2.Content{Language: "fr"}}, want: "de"},
+		{name: "content language", device: &openrtb2.Device{}, site: &openrtb2.Site{Content: &openrtb2.Content{Language: "fr"}}, want: "fr"},
+		{name: "no language", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:     "test-request",
+				Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				Device: tt.device,
+				Site:   tt.site,
+			}
+
+			reqs, _ := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if got := reqs[0].Headers.Get("Accept-Language"); got != tt.want {
+				t.Errorf("Accept-Language = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeBidsDemandChain(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	dchain := `{"complete":1,"nodes":[{"name":"Demand Co","domain":"demand.example.com"}],"ver":"1.0"}`
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"ext":{"dchain":` + dchain + `}},
+			{"id":"bid-2","impid":"imp-1","price":1}
+		]}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if meta := bidResponse.Bids[0].BidMeta; meta == nil || string(meta.DChain) != dchain {
+		t.Errorf("bid-1 meta = %+v, want dchain %s", meta, dchain)
+	}
+	if meta := bidResponse.Bids[1].BidMeta; meta != nil {
+		t.Errorf("bid-2 meta = %+v, want nil", meta)
+	}
+}
+
+func TestMakeRequestsFirstPriceMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		at        int64
+		wantAT    int64
+		wantErr   bool
+	}{
+		{name: "forward", extraInfo: "", at: 2, wantAT: 2},
+		{name: "coerce", extraInfo: `{"firstPriceMode":"coerce"}`, at: 2, wantAT: 1},
+		{name: "reject second price", extraInfo: `{"firstPriceMode":"reject"}`, at: 2, wantErr: true},
+		{name: "reject allows first price", extraInfo: `{"firstPriceMode":"reject"}`, at: 1, wantAT: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				AT:  tt.at,
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if tt.wantErr {
+				if len(reqs) != 0 || len(errs) != 1 {
+					t.Fatalf("expected a single error and no requests, got %d requests, %v", len(reqs), errs)
+				}
+				if _, ok := errs[0].(*errortypes.BadInput); !ok {
+					t.Errorf("expected *errortypes.BadInput, got %T", errs[0])
+				}
+				return
+			}
+
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if sent.AT != tt.wantAT {
+				t.Errorf("at = %d, want %d", sent.AT, tt.wantAT)
+			}
+		})
+	}
+}
+
+// TestMakeBidsNoContent covers the 204 path, which the JSON samples can't
+// express: no bid response and no errors.
+func TestMakeBidsNoContent(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: http.StatusNoContent})
+	if bidResponse != nil {
+		t.Errorf("expected a nil bid response, got %+v", bidResponse)
+	}
+	if errs != nil {
+		t.Errorf("expected nil errors, got %v", errs)
+	}
+}
+
+func TestMakeBidsSplitRequests(t *testing.T) {
+	bidder := newTestBidder(t, `{"rewardedEndpoint":"https://example.com/rewarded"}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":"123"}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Rwdd: 1, Ext: json.RawMessage(`{"bidder":{"placementId":"456"}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+
+	// Both endpoints answer for both imps; each response may only fill its own
+	body := []byte(`{"id":"test-request","seatbid":[{"bid":[
+		{"id":"bid-1","impid":"imp-1","price":1},
+		{"id":"bid-2","impid":"imp-2","price":1}
+	]}]}`)
+	for i, wantImpID := range []string{"imp-1", "imp-2"} {
+		bidResponse, errs := bidder.MakeBids(request, reqs[i], &adapters.ResponseData{StatusCode: http.StatusOK, Body: body})
+		if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ImpID != wantImpID {
+			t.Errorf("request %d: expected a single bid for %s, got %+v", i, wantImpID, bidResponse.Bids)
+		}
+		if len(errs) != 1 {
+			t.Fatalf("request %d: expected 1 error, got %v", i, errs)
+		}
+		if _, ok := errs[0].(*errortypes.BadServerResponse); !ok {
+			t.Errorf("request %d: expected *errortypes.BadServerResponse, got %T", i, errs[0])
+		}
+	}
+}
+
+func TestMakeBidsCategoryMap(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1,"cat":["IAB1-1","IAB2"]}]}]}`),
+	}
+
+	tests := []struct {
+		name        string
+		extraInfo   string
+		wantCat     []string
+		wantPrimary string
+	}{
+		{name: "passthrough by default", wantCat: []string{"IAB1-1", "IAB2"}, wantPrimary: "IAB1-1"},
+		{name: "mapped", extraInfo: `{"categoryMap":{"IAB1-1":"1"}}`, wantCat: []string{"1", "IAB2"}, wantPrimary: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, response)
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			typedBid := bidResponse.Bids[0]
+			if !slices.Equal(typedBid.Bid.Cat, tt.wantCat) {
+				t.Errorf("cat = %v, want %v", typedBid.Bid.Cat, tt.wantCat)
+			}
+			primary := ""
+			if typedBid.BidMeta != nil {
+				primary = typedBid.BidMeta.PrimaryCategoryID
+			}
+			if primary != tt.wantPrimary {
+				t.Errorf("primaryCatId = %q, want %q", primary, tt.wantPrimary)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsRequireBuyerUID(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		user      *openrtb2.User
+		wantErr   bool
+	}{
+		{name: "proceed by default", user: nil},
+		{name: "required and missing", extraInfo: `{"requireBuyerUid":true}`, user: &openrtb2.User{ID: "user-1"}, wantErr: true},
+		{name: "required and present", extraInfo: `{"requireBuyerUid":true}`, user: &openrtb2.User{BuyerUID: "buyer-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:   "test-request",
+				Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				User: tt.user,
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if tt.wantErr {
+				if len(reqs) != 0 || len(errs) != 1 {
+					t.Fatalf("expected a single error and no requests, got %d requests, %v", len(reqs), errs)
+				}
+				if _, ok := errs[0].(*errortypes.BadInput); !ok {
+					t.Errorf("expected *errortypes.BadInput, got %T", errs[0])
+				}
+				return
+			}
+			if len(errs) != 0 || len(reqs) != 1 {
+				t.Fatalf("expected 1 request and no errors, got %d requests, %v", len(reqs), errs)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsInterstitialRouting(t *testing.T) {
+	bidder := newTestBidder(t, `{"interstitialEndpoint":"https://example.com/interstitial","rewardedEndpoint":"https://example.com/rewarded"}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":"123"}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Instl: 1, Ext: json.RawMessage(`{"bidder":{"placementId":"456"}}`)},
+			{ID: "imp-3", Banner: &openrtb2.Banner{}, Instl: 1, Rwdd: 1, Ext: json.RawMessage(`{"bidder":{"placementId":"789"}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	got := make(map[string][]string, len(reqs))
+	for _, req := range reqs {
+		got[req.Uri] = req.ImpIDs
+	}
+	want := map[string][]string{
+		"https://example.com/bid":          {"imp-1"},
+		"https://example.com/interstitial": {"imp-2"},
+		"https://example.com/rewarded":     {"imp-3"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("requests = %v, want %v", got, want)
+	}
+	for uri, impIDs := range want {
+		if !slices.Equal(got[uri], impIDs) {
+			t.Errorf("%s imps = %v, want %v", uri, got[uri], impIDs)
+		}
+	}
+}
+
+func TestMakeBidsRedirect(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusFound,
+		Headers:    http.Header{"Location": []string{"https://eu.example.com/bid"}},
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if bidResponse != nil {
+		t.Errorf("expected a nil bid response, got %+v", bidResponse)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadServerResponse); !ok {
+		t.Errorf("expected *errortypes.BadServerResponse, got %T", errs[0])
+	}
+	if !strings.Contains(errs[0].Error(), "https://eu.example.com/bid") {
+		t.Errorf("error %q does not name the redirect target", errs[0])
+	}
+}
+
+func TestMakeBidsBidExtCurrency(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantCur string
+	}{
+		{
+			name:    "from bid.ext",
+			body:    `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1,"ext":{"cur":"EUR"}}]}]}`,
+			wantCur: "EUR",
+		},
+		{
+			name:    "response cur wins",
+			body:    `{"id":"test-request","cur":"GBP","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1,"ext":{"cur":"EUR"}}]}]}`,
+			wantCur: "GBP",
+		},
+		{
+			name:    "defaults to USD",
+			body:    `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1}]}]}`,
+			wantCur: "USD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(tt.body)})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if bidResponse.Currency != tt.wantCur {
+				t.Errorf("currency = %s, want %s", bidResponse.Currency, tt.wantCur)
+			}
+		})
+	}
+}
+
+func TestBuilderStrictTLS(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		extraInfo string
+		wantErr   bool
+	}{
+		{name: "http allowed by default", endpoint: "http://example.com/bid"},
+		{name: "https endpoint", endpoint: "https://example.com/bid", extraInfo: `{"strictTLS":true}`},
+		{name: "http endpoint", endpoint: "http://example.com/bid", extraInfo: `{"strictTLS":true}`, wantErr: true},
+		{
+			name:      "http failover endpoint",
+			endpoint:  "https://example.com/bid",
+			extraInfo: `{"strictTLS":true,"failoverEndpoints":["http://backup.example.com/bid"]}`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, buildErr := Builder(
+				openrtb_ext.Bidder{{NAME}},
+				config.Adapter{Endpoint: tt.endpoint, ExtraAdapterInfo: tt.extraInfo},
+				config.Server{},
+			)
+			if (buildErr != nil) != tt.wantErr {
+				t.Errorf("Builder error = %v, wantErr %v", buildErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsServerInfoHeaders(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		Ext: json.RawMessage(`{"prebid":{"server":{"externalurl":"https://pbs.example.com","gvlid":1,"datacenter":"us-east"}}}`),
+	}
+
+	tests := []struct {
+		name           string
+		extraInfo      string
+		wantURL        string
+		wantDataCenter string
+	}{
+		{name: "off by default"},
+		{name: "forwarded", extraInfo: `{"serverInfoHeaders":true}`, wantURL: "https://pbs.example.com", wantDataCenter: "us-east"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := reqs[0].Headers.Get(ServerURLHeader); got != tt.wantURL {
+				t.Errorf("%s = %q, want %q", ServerURLHeader, got, tt.wantURL)
+			}
+			if got := reqs[0].Headers.Get(ServerDataCenterHeader); got != tt.wantDataCenter {
+				t.Errorf("%s = %q, want %q", ServerDataCenterHeader, got, tt.wantDataCenter)
+			}
+		})
+	}
+}
+
+func TestMakeBidsVideoCompanion(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	companionW, companionH := int64(300), int64(250)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{
+			MIMEs:       []string{"video/mp4"},
+			CompanionAd: []openrtb2.Banner{{W: &companionW, H: &companionH}},
+		}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"video","impid":"imp-1","price":2,"w":640,"h":480,"adm":"<VAST version=\"3.0\"></VAST>"},
+			{"id":"companion-size","impid":"imp-1","price":1,"w":300,"h":250,"adm":"<div>companion</div>"},
+			{"id":"companion-ext","impid":"imp-1","price":1,"w":728,"h":90,"adm":"<div>companion</div>","ext":{"companion":true}}
+		]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := map[string]openrtb_ext.BidType{
+		"video":          openrtb_ext.BidTypeVideo,
+		"companion-size": openrtb_ext.BidTypeBanner,
+		"companion-ext":  openrtb_ext.BidTypeBanner,
+	}
+	if len(bidResponse.Bids) != len(want) {
+		t.Fatalf("expected %d bids, got %d", len(want), len(bidResponse.Bids))
+	}
+	for _, typedBid := range bidResponse.Bids {
+		if typedBid.BidType != want[typedBid.Bid.ID] {
+			t.Errorf("bid %s: type = %s, want %s", typedBid.Bid.ID, typedBid.BidType, want[typedBid.Bid.ID])
+		}
+	}
+}
+
+func TestMakeRequestsDefaultKeywords(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantSite  string
+		wantUser  string
+	}{
+		{name: "forward by default", wantSite: "sports, news", wantUser: "cars"},
+		{name: "merged", extraInfo: `{"defaultKeywords":["news","acme"]}`, wantSite: "sports,news,acme", wantUser: "cars"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:   "test-request",
+				Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				Site: &openrtb2.Site{Page: "https://example.com", Keywords: "sports, news"},
+				User: &openrtb2.User{Keywords: "cars"},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if sent.Site.Keywords != tt.wantSite {
+				t.Errorf("site.keywords = %q, want %q", sent.Site.Keywords, tt.wantSite)
+			}
+			if sent.User.Keywords != tt.wantUser {
+				t.Errorf("user.keywords = %q, want %q", sent.User.Keywords, tt.wantUser)
+			}
+			if request.Site.Keywords != "sports, news" {
+				t.Errorf("core site.keywords modified: %q", request.Site.Keywords)
+			}
+		})
+	}
+}
+
+func TestMakeBidsSeatDealMeta(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[
+			{"seat":"deals","bid":[
+				{"id":"bid-1","impid":"imp-1","price":1,"dealid":"deal-1"},
+				{"id":"bid-2","impid":"imp-1","price":1,"dealid":"deal-1","ext":{"dchain":{"ver":"1.0"}}}
+			],"ext":{"deal":{"demandSource":"pmp","networkName":"Acme Deals"}}},
+			{"seat":"open","bid":[{"id":"bid-3","impid":"imp-1","price":1}]}
+		]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 3 {
+		t.Fatalf("expected 3 bids, got %d", len(bidResponse.Bids))
+	}
+	for _, typedBid := range bidResponse.Bids[:2] {
+		meta := typedBid.BidMeta
+		if meta == nil || meta.DemandSource != "pmp" || meta.NetworkName != "Acme Deals" {
+			t.Errorf("bid %s meta = %+v, want the seat deal meta", typedBid.Bid.ID, meta)
+		}
+	}
+	if meta := bidResponse.Bids[1].BidMeta; meta == nil || len(meta.DChain) == 0 {
+		t.Errorf("bid-2 lost its dchain: %+v", meta)
+	}
+	if meta := bidResponse.Bids[2].BidMeta; meta == nil || meta.Seat != "open" || meta.DemandSource != "" || meta.NetworkName != "" {
+		t.Errorf("bid-3 meta = %+v, want only its seat", meta)
+	}
+}
+
+func TestMakeBidsAdvertiserMeta(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"adomain":["advertiser.com"],"cat":["IAB1","IAB2"],"ext":{"dchain":{"ver":"1.0"}}}
+		],"ext":{"deal":{"demandSource":"pmp"}}}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	meta := bidResponse.Bids[0].BidMeta
+	if meta == nil {
+		t.Fatal("expected bid meta")
+	}
+	if !slices.Equal(meta.AdvertiserDomains, []string{"advertiser.com"}) {
+		t.Errorf("advertiserDomains = %v", meta.AdvertiserDomains)
+	}
+	if meta.PrimaryCategoryID != "IAB1" || !slices.Equal(meta.SecondaryCategoryIDs, []string{"IAB2"}) {
+		t.Errorf("categories = %q %v", meta.PrimaryCategoryID, meta.SecondaryCategoryIDs)
+	}
+	if meta.DemandSource != "pmp" || len(meta.DChain) == 0 {
+		t.Errorf("seat meta or dchain lost: %+v", meta)
+	}
+}
+
+func TestMakeBidsBidMetrics(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}},
+			{ID: "imp-2", Video: &openrtb2.Video{}},
+		},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[
+			{"seat":"acme-demand","bid":[{"id":"bid-1","impid":"imp-1","price":1}]},
+			{"bid":[{"id":"bid-2","impid":"imp-2","price":1}]}
+		]}`),
+	}
+
+	tests := []struct {
+		name       string
+		extraInfo  string
+		wantLabels []map[string]string
+	}{
+		{name: "off by default"},
+		{
+			name:      "labelled",
+			extraInfo: `{"bidMetrics":true}`,
+			wantLabels: []map[string]string{
+				{"bidder": string(openrtb_ext.Bidder{{NAME}}), "media_type": "banner", "seat": "acme-demand"},
+				{"bidder": string(openrtb_ext.Bidder{{NAME}}), "media_type": "video", "seat": string(openrtb_ext.Bidder{{NAME}})},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded []map[string]string
+			bidder, buildErr := MetricsBuilder(func(labels map[string]string) {
+				recorded = append(recorded, labels)
+			})(
+				openrtb_ext.Bidder{{NAME}},
+				config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: tt.extraInfo},
+				config.Server{},
+			)
+			if buildErr != nil {
+				t.Fatalf("MetricsBuilder returned unexpected error: %v", buildErr)
+			}
+
+			if _, errs := bidder.MakeBids(request, &adapters.RequestData{}, response); len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(recorded) != len(tt.wantLabels) {
+				t.Fatalf("recorded %v, want %v", recorded, tt.wantLabels)
+			}
+			for i, labels := range tt.wantLabels {
+				for key, value := range labels {
+					if recorded[i][key] != value {
+						t.Errorf("bid %d label %s = %q, want %q", i, key, recorded[i][key], value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMakeRequestsGenerateTIDs(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"tid":"imp-tid-1","bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		extraInfo  string
+		wantSource string
+		wantTIDs   []string
+	}{
+		{name: "preserved by default", wantTIDs: []string{"imp-tid-1", ""}},
+		{name: "generated", extraInfo: `{"generateTids":true}`, wantSource: "generated-1", wantTIDs: []string{"imp-tid-1", "generated-0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidder := newTestBidder(t, tt.extraInfo).(*adapter)
+			generated := 0
+			bidder.newTID = func() (string, error) {
+				tid := fmt.Sprintf("generated-%d", generated)
+				generated++
+				return tid, nil
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+
+			sourceTID := ""
+			if sent.Source != nil {
+				sourceTID = sent.Source.TID
+			}
+			if sourceTID != tt.wantSource {
+				t.Errorf("source.tid = %q, want %q", sourceTID, tt.wantSource)
+			}
+			for i, wantTID := range tt.wantTIDs {
+				var impExt struct {
+					TID string `json:"tid"`
+				}
+				if err := json.Unmarshal(sent.Imp[i].Ext, &impExt); err != nil {
+					t.Fatalf("failed to decode imp.ext: %v", err)
+				}
+				if impExt.TID != wantTID {
+					t.Errorf("imp %d ext.tid = %q, want %q", i, impExt.TID, wantTID)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomTID(t *testing.T) {
+	tid, err := randomTID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(tid) {
+		t.Errorf("tid %q is not a version 4 UUID", tid)
+	}
+}
+
+func TestMakeRequestsSupportedMediaTypes(t *testing.T) {
+	bidder := newTestBidder(t, `{"supportedMediaTypes":["banner","video"]}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Native: &openrtb2.Native{Request: "{}"}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Native: &openrtb2.Native{Request: "{}"}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the native-only imp, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadInput); !ok {
+		t.Errorf("expected *errortypes.BadInput, got %T", errs[0])
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 1 || sent.Imp[0].ID != "imp-1" {
+		t.Fatalf("sent imps = %+v, want only imp-1", sent.Imp)
+	}
+	if sent.Imp[0].Native != nil || sent.Imp[0].Banner == nil {
+		t.Errorf("imp-1 = %+v, want banner only", sent.Imp[0])
+	}
+	if request.Imp[0].Native == nil {
+		t.Error("core imp was modified")
+	}
+}
+
+func TestBuilderInvalidSupportedMediaTypes(t *testing.T) {
+	_, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: `{"supportedMediaTypes":["display"]}`},
+		config.Server{},
+	)
+	if buildErr == nil {
+		t.Fatal("expected an error for an unknown supportedMediaTypes entry")
+	}
+}
+
+func TestMakeRequestsMaxImps(t *testing.T) {
+	bidder := newTestBidder(t, `{"maxImps":2}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: 0.5, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, BidFloor: 2, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-3", Banner: &openrtb2.Banner{}, BidFloor: 0.1, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-4", Banner: &openrtb2.Banner{}, BidFloor: 1, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok {
+		t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+	}
+	if want := []string{"imp-2", "imp-4"}; !slices.Equal(reqs[0].ImpIDs, want) {
+		t.Errorf("sent imps = %v, want %v", reqs[0].ImpIDs, want)
+	}
+}
+
+func TestMakeRequestsEnforceLMT(t *testing.T) {
+	lmt := int8(1)
+	request := &openrtb2.BidRequest{
+		ID:     "test-request",
+		Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		Device: &openrtb2.Device{Lmt: &lmt, IFA: "ifa-1", DPIDMD5: "dpid-1", UA: "test-ua"},
+		User:   &openrtb2.User{BuyerUID: "buyer-1"},
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantIFA   string
+		wantUser  bool
+	}{
+		{name: "forward by default", wantIFA: "ifa-1", wantUser: true},
+		{name: "strip ids", extraInfo: `{"enforceLmt":true}`, wantUser: true},
+		{name: "strip ids and user", extraInfo: `{"enforceLmt":true,"lmtDropUser":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if sent.Device.IFA != tt.wantIFA {
+				t.Errorf("device.ifa = %q, want %q", sent.Device.IFA, tt.wantIFA)
+			}
+			if tt.wantIFA == "" && sent.Device.DPIDMD5 != "" {
+				t.Errorf("device.dpidmd5 = %q, want it stripped", sent.Device.DPIDMD5)
+			}
+			if sent.Device.UA != "test-ua" {
+				t.Errorf("device.ua = %q, want it kept", sent.Device.UA)
+			}
+			if (sent.User != nil) != tt.wantUser {
+				t.Errorf("user sent = %v, want %v", sent.User != nil, tt.wantUser)
+			}
+			if request.Device.IFA != "ifa-1" {
+				t.Error("core device was modified")
+			}
+		})
+	}
+}
+
+func TestMakeRequestsRequiredContentFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		extraInfo    string
+		content      *openrtb2.Content
+		wantWarnings int
+	}{
+		{name: "not validated by default", content: &openrtb2.Content{Series: "Show"}},
+		{
+			name:      "complete",
+			extraInfo: `{"requiredContentFields":["series","season","genre"]}`,
+			content:   &openrtb2.Content{Series: "Show", Season: "2", Genre: "Drama"},
+		},
+		{
+			name:         "missing fields",
+			extraInfo:    `{"requiredContentFields":["series","season","genre"]}`,
+			content:      &openrtb2.Content{Series: "Show"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "missing content",
+			extraInfo:    `{"requiredContentFields":["series"]}`,
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+				App: &openrtb2.App{Bundle: "com.example.ctv", Content: tt.content},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			warnings := 0
+			for _, err := range errs {
+				if w, ok := err.(*errortypes.Warning); ok && strings.Contains(w.Message, "content") {
+					warnings++
+				}
+			}
+			if warnings != tt.wantWarnings {
+				t.Errorf("content warnings = %d, want %d: %v", warnings, tt.wantWarnings, errs)
+			}
+
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if tt.content != nil && (sent.App.Content == nil || sent.App.Content.Series != tt.content.Series) {
+				t.Errorf("app.content = %+v, want it preserved", sent.App.Content)
+			}
+		})
+	}
+}
+
+// TestMakeBidsPartialResponse checks a bad bid only costs itself: the good
+// bids are returned alongside its warning.
+func TestMakeBidsPartialResponse(t *testing.T) {
+	bidder := newTestBidder(t, `{"blockedAdmPatterns":["malware\\.example"]}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}},
+			{ID: "imp-3"},
+		},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"adm":"<div>ok</div>"},
+			{"id":"bid-2","impid":"imp-2","price":1,"adm":"<script src=\"https://malware.example/x.js\"></script>"},
+			{"id":"bid-3","impid":"imp-2","price":1,"adm":"<div>ok</div>"},
+			{"id":"bid-4","impid":"imp-3","price":1,"adm":"<div>ok</div>"}
+		]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", errs)
+	}
+	for i, wantBid := range []string{"bid-2", "bid-4"} {
+		if _, ok := errs[i].(*errortypes.Warning); !ok || !strings.Contains(errs[i].Error(), wantBid) {
+			t.Errorf("expected a Warning naming %s, got %T %v", wantBid, errs[i], errs[i])
+		}
+	}
+	if len(bidResponse.Bids) != 2 || bidResponse.Bids[0].Bid.ID != "bid-1" || bidResponse.Bids[1].Bid.ID != "bid-3" {
+		t.Errorf("expected bid-1 and bid-3, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestMakeBidsEvents(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"ext":{"events":{"win":"https://t.example.com/win","imp":"https://t.example.com/imp"},"foo":1}},
+			{"id":"bid-2","impid":"imp-1","price":1,"ext":{"prebid":{"events":{"win":"https://core.example.com/win"}},"events":{"win":"https://t.example.com/win"}}},
+			{"id":"bid-3","impid":"imp-1","price":1}
+		]}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	var ext struct {
+		Events json.RawMessage          `json:"events"`
+		Foo    int                      `json:"foo"`
+		Prebid openrtb_ext.ExtBidPrebid `json:"prebid"`
+	}
+	if err := json.Unmarshal(bidResponse.Bids[0].Bid.Ext, &ext); err != nil {
+		t.Fatalf("failed to decode bid-1 ext: %v", err)
+	}
+	if events := ext.Prebid.Events; events == nil || events.Win != "https://t.example.com/win" || events.Imp != "https://t.example.com/imp" {
+		t.Errorf("bid-1 prebid.events = %+v", events)
+	}
+	if ext.Events != nil || ext.Foo != 1 {
+		t.Errorf("bid-1 ext = %s, want events moved and other keys kept", bidResponse.Bids[0].Bid.Ext)
+	}
+
+	if err := json.Unmarshal(bidResponse.Bids[1].Bid.Ext, &ext); err != nil {
+		t.Fatalf("failed to decode bid-2 ext: %v", err)
+	}
+	if events := ext.Prebid.Events; events == nil || events.Win != "https://core.example.com/win" {
+		t.Errorf("bid-2 prebid.events = %+v, want the existing value kept", events)
+	}
+
+	if bidResponse.Bids[2].Bid.Ext != nil {
+		t.Errorf("bid-3 ext = %s, want nil", bidResponse.Bids[2].Bid.Ext)
+	}
+}
+
+func TestMakeRequestsKeepAlive(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		want      string
+	}{
+		{name: "off by default", want: ""},
+		{name: "enabled", extraInfo: `{"keepAlive":true}`, want: "keep-alive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := reqs[0].Headers.Get("Connection"); got != tt.want {
+				t.Errorf("Connection = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsSplitByFloorCurrency(t *testing.T) {
+	bidder := newTestBidder(t, `{"splitByFloorCurrency":true}`)
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: 1, BidFloorCur: "EUR", Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, BidFloor: 1, BidFloorCur: "USD", Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-3", Banner: &openrtb2.Banner{}, BidFloor: 2, BidFloorCur: "EUR", Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-4", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+		Cur: []string{"USD", "EUR"},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+
+	want := []struct {
+		cur    string
+		impIDs []string
+	}{
+		{cur: "EUR", impIDs: []string{"imp-1", "imp-3"}},
+		{cur: "USD", impIDs: []string{"imp-2", "imp-4"}},
+	}
+	for i, w := range want {
+		var sent openrtb2.BidRequest
+		if err := json.Unmarshal(reqs[i].Body, &sent); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if !slices.Equal(sent.Cur, []string{w.cur}) {
+			t.Errorf("request %d cur = %v, want [%s]", i, sent.Cur, w.cur)
+		}
+		if !slices.Equal(reqs[i].ImpIDs, w.impIDs) {
+			t.Errorf("request %d imps = %v, want %v", i, reqs[i].ImpIDs, w.impIDs)
+		}
+	}
+}
+
+func TestMakeRequestsDefaultDisplayManager(t *testing.T) {
+	bidder := newTestBidder(t, `{"defaultDisplayManager":"acme-sdk","defaultDisplayManagerVer":"2.1"}`)
+
+	tests := []struct {
+		name    string
+		app     *openrtb2.App
+		site    *openrtb2.Site
+		imp     openrtb2.Imp
+		wantDM  string
+		wantVer string
+	}{
+		{
+			name:    "defaulted for app",
+			app:     &openrtb2.App{Bundle: "com.example.app"},
+			imp:     openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			wantDM:  "acme-sdk",
+			wantVer: "2.1",
+		},
+		{
+			name:    "forwarded from imp",
+			app:     &openrtb2.App{Bundle: "com.example.app"},
+			imp:     openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{}, DisplayManager: "other-sdk", DisplayManagerVer: "9", Ext: json.RawMessage(`{"bidder":{}}`)},
+			wantDM:  "other-sdk",
+			wantVer: "9",
+		},
+		{
+			name: "not defaulted for site",
+			site: &openrtb2.Site{Page: "https://example.com"},
+			imp:  openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{ID: "test-request", Imp: []openrtb2.Imp{tt.imp}, App: tt.app, Site: tt.site}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if sent.Imp[0].DisplayManager != tt.wantDM || sent.Imp[0].DisplayManagerVer != tt.wantVer {
+				t.Errorf("displaymanager = %q %q, want %q %q", sent.Imp[0].DisplayManager, sent.Imp[0].DisplayManagerVer, tt.wantDM, tt.wantVer)
+			}
+		})
+	}
+}
+
+// TestMakeBidsMultibid checks every bid on an imp reaches the core, which
+// applies ext.prebid.multibid limits and targeting itself.
+func TestMakeBidsMultibid(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+		Ext: json.RawMessage(`{"prebid":{"multibid":[{"bidder":"{{NAME_LOWER}}","maxbids":3}]}}`),
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":3,"crid":"creative-1"},
+			{"id":"bid-2","impid":"imp-1","price":2,"crid":"creative-2"},
+			{"id":"bid-3","impid":"imp-1","price":1,"crid":"creative-3"}
+		]}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 3 {
+		t.Fatalf("expected 3 bids, got %d", len(bidResponse.Bids))
+	}
+	for i, typedBid := range bidResponse.Bids {
+		if want := fmt.Sprintf("bid-%d", i+1); typedBid.Bid.ID != want || typedBid.Bid.ImpID != "imp-1" {
+			t.Errorf("bid %d = %s on %s, want %s on imp-1", i, typedBid.Bid.ID, typedBid.Bid.ImpID, want)
+		}
+	}
+}
+
+// TestMakeRequestsPreservesCacheDirective checks ext.prebid.cache reaches the
+// endpoint untouched, including when imp.ext.prebid is stripped.
+func TestMakeRequestsPreservesCacheDirective(t *testing.T) {
+	cache := `{"bids":{},"vastxml":{"returnCreative":true}}`
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"prebid":{},"bidder":{}}`)}},
+		Ext: json.RawMessage(`{"prebid":{"cache":` + cache + `}}`),
+	}
+
+	for _, extraInfo := range []string{"", `{"stripImpExtPrebid":true}`} {
+		reqs, errs := newTestBidder(t, extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+		if len(errs) != 0 {
+			t.Fatalf("%q: unexpected errors: %v", extraInfo, errs)
+		}
+		var sent struct {
+			Ext struct {
+				Prebid struct {
+					Cache json.RawMessage `json:"cache"`
+				} `json:"prebid"`
+			} `json:"ext"`
+		}
+		if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if string(sent.Ext.Prebid.Cache) != cache {
+			t.Errorf("%q: ext.prebid.cache = %s, want %s", extraInfo, sent.Ext.Prebid.Cache, cache)
+		}
+	}
+}
+
+func TestMakeBidsEnforceBlockLists(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+		BAdv: []string{"blocked.com"},
+		BCat: []string{"IAB25"},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"adomain":["ok.com"]},
+			{"id":"bid-2","impid":"imp-1","price":1,"adomain":["Blocked.com"]},
+			{"id":"bid-3","impid":"imp-1","price":1,"adomain":["ok.com"],"cat":["IAB25"]}
+		]}]}`),
+	}
+
+	bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", errs)
+	}
+	for _, err := range errs {
+		if _, ok := err.(*errortypes.Warning); !ok {
+			t.Errorf("expected *errortypes.Warning, got %T", err)
+		}
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-1" {
+		t.Errorf("expected only bid-1, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestMakeBidsEnforceMergedBlockLists(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+		BAdv: []string{"blocked.com"},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"bid-1","impid":"imp-1","price":1,"adomain":["ok.com"]},
+			{"id":"bid-2","impid":"imp-1","price":1,"adomain":["default.com"]},
+			{"id":"bid-3","impid":"imp-1","price":1,"adomain":["ok.com"],"cat":["IAB26"]}
+		]}]}`),
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantBids  []string
+	}{
+		{name: "defaults not sent", extraInfo: `{"defaultBAdv":["default.com"],"defaultBCat":["IAB26"]}`, wantBids: []string{"bid-1", "bid-2", "bid-3"}},
+		{name: "merged defaults enforced", extraInfo: `{"blockListMode":"merge","defaultBAdv":["default.com"],"defaultBCat":["IAB26"]}`, wantBids: []string{"bid-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, response)
+			if len(errs) != 3-len(tt.wantBids) {
+				t.Fatalf("expected %d warnings, got %v", 3-len(tt.wantBids), errs)
+			}
+			var got []string
+			for _, typedBid := range bidResponse.Bids {
+				got = append(got, typedBid.Bid.ID)
+			}
+			if !slices.Equal(got, tt.wantBids) {
+				t.Errorf("bids = %v, want %v", got, tt.wantBids)
+			}
+		})
+	}
+}
+
+func TestMakeBidsMaxResponseBytes(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	body := []byte(`{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1}]}]}`)
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantErr   bool
+	}{
+		{name: "unlimited by default"},
+		{name: "within limit", extraInfo: fmt.Sprintf(`{"maxResponseBytes":%d}`, len(body))},
+		{name: "oversized", extraInfo: fmt.Sprintf(`{"maxResponseBytes":%d}`, len(body)-1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: http.StatusOK, Body: body})
+			if !tt.wantErr {
+				if len(errs) != 0 || len(bidResponse.Bids) != 1 {
+					t.Fatalf("expected 1 bid and no errors, got %+v, %v", bidResponse, errs)
+				}
+				return
+			}
+			if bidResponse != nil || len(errs) != 1 {
+				t.Fatalf("expected a single error and no response, got %+v, %v", bidResponse, errs)
+			}
+			if _, ok := errs[0].(*errortypes.BadServerResponse); !ok {
+				t.Errorf("expected *errortypes.BadServerResponse, got %T", errs[0])
+			}
+		})
+	}
+}
+
+func TestMakeRequestsSKAdN(t *testing.T) {
+	skadn := `{"versions":["2.0","4.0"],"sourceapp":"880047117","skadnetids":["cstr6suwn9.skadnetwork"]}`
+
+	tests := []struct {
+		name         string
+		extraInfo    string
+		skadn        string
+		wantWarnings int
+	}{
+		{name: "preserved without validation", skadn: `{"versions":["4.0"]}`},
+		{name: "complete", extraInfo: `{"validateSkadn":true}`, skadn: skadn},
+		{name: "incomplete", extraInfo: `{"validateSkadn":true}`, skadn: `{"versions":["4.0"]}`, wantWarnings: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"skadn":` + tt.skadn + `,"bidder":{}}`)}},
+				App: &openrtb2.App{Bundle: "880047117"},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != tt.wantWarnings {
+				t.Fatalf("errors = %v, want %d warnings", errs, tt.wantWarnings)
+			}
+			var sent struct {
+				Imp []struct {
+					Ext struct {
+						SKAdN json.RawMessage `json:"skadn"`
+					} `json:"ext"`
+				} `json:"imp"`
+			}
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if string(sent.Imp[0].Ext.SKAdN) != tt.skadn {
+				t.Errorf("imp.ext.skadn = %s, want %s", sent.Imp[0].Ext.SKAdN, tt.skadn)
+			}
+		})
+	}
+}
+
+func TestMakeBidsSortDeals(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[
+			{"id":"open","impid":"imp-1","price":5},
+			{"id":"deal-low","impid":"imp-1","price":1,"dealid":"deal-1","ext":{"dealtier":1}},
+			{"id":"deal-high","impid":"imp-1","price":1,"dealid":"deal-2","ext":{"dealtier":5}}
+		]}]}`),
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		wantIDs   []string
+	}{
+		{name: "response order by default", wantIDs: []string{"open", "deal-low", "deal-high"}},
+		{name: "deals first", extraInfo: `{"sortDeals":true}`, wantIDs: []string{"deal-high", "deal-low", "open"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := newTestBidder(t, tt.extraInfo).MakeBids(request, &adapters.RequestData{}, response)
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var ids []string
+			for _, typedBid := range bidResponse.Bids {
+				ids = append(ids, typedBid.Bid.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("bid order = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsDropDeprecatedFields(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{
+			MIMEs:     []string{"video/mp4"},
+			Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30},
+			Protocol:  adcom1.CreativeVAST30,
+			Plcmt:     adcom1.VideoPlcmtInstream,
+			Placement: adcom1.VideoPlacementSubtype(1),
+		}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		User: &openrtb2.User{ID: "user-1", Yob: 1980, Gender: "F"},
+	}
+
+	tests := []struct {
+		name        string
+		extraInfo   string
+		wantDropped bool
+	}{
+		{name: "forwarded by default"},
+		{name: "dropped", extraInfo: `{"dropDeprecatedFields":true}`, wantDropped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			video := sent.Imp[0].Video
+			dropped := video.Placement == 0 && video.Protocol == 0 && sent.User.Yob == 0 && sent.User.Gender == ""
+			if dropped != tt.wantDropped {
+				t.Errorf("deprecated fields dropped = %v, want %v: video %+v, user %+v", dropped, tt.wantDropped, video, sent.User)
+			}
+			if video.Plcmt != adcom1.VideoPlcmtInstream || len(video.Protocols) != 1 || sent.User.ID != "user-1" {
+				t.Errorf("current fields lost: video %+v, user %+v", video, sent.User)
+			}
+			if request.Imp[0].Video.Placement == 0 || request.User.Yob == 0 {
+				t.Error("core request was modified")
+			}
+		})
+	}
+}
+
+func TestMakeRequestsPreservesAllowlists(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:    "test-request",
+		Imp:   []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		WSeat: []string{"seat-1", "seat-2"},
+		WLang: []string{"en", "fr"},
+	}
+
+	reqs, errs := newTestBidder(t, `{"blockListMode":"drop"}`).MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if !slices.Equal(sent.WSeat, request.WSeat) {
+		t.Errorf("wseat = %v, want %v", sent.WSeat, request.WSeat)
+	}
+	if !slices.Equal(sent.WLang, request.WLang) {
+		t.Errorf("wlang = %v, want %v", sent.WLang, request.WLang)
+	}
+}
+
+func TestMakeBidsCooldown(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name         string
+		status       int
+		retryAfter   string
+		wantCooldown time.Duration
+	}{
+		{name: "503 without Retry-After", status: http.StatusServiceUnavailable, wantCooldown: defaultCooldown},
+		{name: "503 with Retry-After", status: http.StatusServiceUnavailable, retryAfter: "30", wantCooldown: 30 * time.Second},
+		{name: "429 with Retry-After", status: http.StatusTooManyRequests, retryAfter: "2", wantCooldown: 2 * time.Second},
+		{name: "500 with unparseable Retry-After", status: http.StatusInternalServerError, retryAfter: "soon", wantCooldown: defaultCooldown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &adapters.ResponseData{StatusCode: tt.status, Headers: http.Header{}}
+			if tt.retryAfter != "" {
+				response.Headers.Set("Retry-After", tt.retryAfter)
+			}
+
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+			if bidResponse != nil {
+				t.Errorf("expected a nil bid response, got %+v", bidResponse)
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			cooldownErr, ok := errs[0].(*CooldownError)
+			if !ok {
+				t.Fatalf("expected *CooldownError, got %T", errs[0])
+			}
+			if cooldownErr.Cooldown != tt.wantCooldown {
+				t.Errorf("expected cooldown %s, got %s", tt.wantCooldown, cooldownErr.Cooldown)
+			}
+			if !strings.Contains(cooldownErr.Error(), tt.wantCooldown.String()) {
+				t.Errorf("error %q does not state the cooldown", cooldownErr)
+			}
+			if code := errortypes.ReadCode(errs[0]); code != errortypes.BadServerResponseErrorCode {
+				t.Errorf("error code = %d, want BadServerResponseErrorCode", code)
+			}
+			var badServerResponse *errortypes.BadServerResponse
+			if !errors.As(errs[0], &badServerResponse) {
+				t.Errorf("errors.As does not find the BadServerResponse")
+			}
+		})
+	}
+}
+
+func TestMakeRequestsDOOHQty(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		DOOH: &openrtb2.DOOH{ID: "screen-1"},
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Qty: &openrtb2.Qty{Multiplier: 12.5, Vendor: "measurer.example"}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Qty: &openrtb2.Qty{Multiplier: 0}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadInput); !ok || !strings.Contains(errs[0].Error(), "imp-2") {
+		t.Errorf("expected a BadInput for imp-2, got %T %v", errs[0], errs[0])
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 1 || sent.Imp[0].ID != "imp-1" {
+		t.Fatalf("expected only imp-1 to be sent, got %+v", sent.Imp)
+	}
+	if qty := sent.Imp[0].Qty; qty == nil || qty.Multiplier != 12.5 || qty.Vendor != "measurer.example" {
+		t.Errorf("qty not preserved, got %+v", qty)
+	}
+}
+
+func TestMakeBidsPrebidBidID(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[` +
+			`{"id":"bid-1","impid":"imp-1","price":1,"ext":{"prebid":{"bidid":"stable-1"}}},` +
+			`{"id":"bid-2","impid":"imp-1","price":1}]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(bidResponse.Bids) != 2 {
+		t.Fatalf("expected 2 bids, got %d", len(bidResponse.Bids))
+	}
+	if id := bidResponse.Bids[0].Bid.ID; id != "stable-1" {
+		t.Errorf("expected bid ID from bid.ext.prebid.bidid, got %q", id)
+	}
+	if id := bidResponse.Bids[1].Bid.ID; id != "bid-2" {
+		t.Errorf("expected bid ID to be kept without a bidid, got %q", id)
+	}
+}
+
+func TestMakeBidsAllowedSeats(t *testing.T) {
+	bidder := newTestBidder(t, `{"allowedSeats":["seat-a"]}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[` +
+			`{"seat":"seat-a","bid":[{"id":"bid-1","impid":"imp-1","price":1}]},` +
+			`{"seat":"seat-b","bid":[{"id":"bid-2","impid":"imp-1","price":2}]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), "seat-b") {
+		t.Errorf("expected a Warning naming seat-b, got %T %v", errs[0], errs[0])
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-1" {
+		t.Errorf("expected only bid-1 from seat-a, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestMakeRequestsFloorPrecision(t *testing.T) {
+	bidder := newTestBidder(t, `{"floorPrecision":2}`)
+
+	tests := []struct {
+		floor float64
+		want  float64
+	}{
+		{floor: 1.23456, want: 1.24},
+		{floor: 1.1, want: 1.1},
+		{floor: 0.001, want: 0.01},
+		{floor: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		request := &openrtb2.BidRequest{
+			ID:  "test-request",
+			Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: tt.floor, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		}
+
+		reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		var sent openrtb2.BidRequest
+		if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if sent.Imp[0].BidFloor != tt.want {
+			t.Errorf("bidfloor %v: got %v, want %v", tt.floor, sent.Imp[0].BidFloor, tt.want)
+		}
+		if request.Imp[0].BidFloor != tt.floor {
+			t.Errorf("bidfloor %v: the core's imp was modified", tt.floor)
+		}
+	}
+}
+
+func TestMakeRequestsFloorCurrency(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: 2, BidFloorCur: "EUR", Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, BidFloor: 2, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-3", Banner: &openrtb2.Banner{}, BidFloor: 2, BidFloorCur: "GBP", Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+	reqInfo := adapters.NewExtraRequestInfo(currency.NewRates(map[string]map[string]float64{
+		"EUR": {"USD": 1.25},
+	}))
+
+	reqs, errs := newTestBidder(t, `{"floorCurrency":"USD"}`).MakeRequests(request, &reqInfo)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for imp-3, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadInput); !ok {
+		t.Errorf("expected *errortypes.BadInput, got %T", errs[0])
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 2 {
+		t.Fatalf("expected imp-1 and imp-2 to be sent, got %d imps", len(sent.Imp))
+	}
+	for i, want := range []float64{2.5, 2} {
+		if imp := sent.Imp[i]; imp.BidFloor != want || imp.BidFloorCur != "USD" {
+			t.Errorf("imp %s floor = %v %s, want %v USD", imp.ID, imp.BidFloor, imp.BidFloorCur, want)
+		}
+	}
+	if request.Imp[0].BidFloor != 2 || request.Imp[0].BidFloorCur != "EUR" {
+		t.Errorf("the core's imp was modified")
+	}
+}
+
+// countingRates counts the rate lookups MakeRequests makes through reqInfo
+type countingRates struct {
+	*currency.Rates
+	lookups int
+}
+
+func (r *countingRates) GetRate(from, to string) (float64, error) {
+	r.lookups++
+	return r.Rates.GetRate(from, to)
+}
+
+func TestMakeRequestsFloorCurrencyRateLookups(t *testing.T) {
+	request := &openrtb2.BidRequest{ID: "test-request"}
+	for i := 0; i < 20; i++ {
+		request.Imp = append(request.Imp, openrtb2.Imp{
+			ID:          fmt.Sprintf("imp-%d", i),
+			Banner:      &openrtb2.Banner{},
+			BidFloor:    float64(i + 1),
+			BidFloorCur: []string{"EUR", "GBP", "USD"}[i%3],
+			Ext:         json.RawMessage(`{"bidder":{}}`),
+		})
+	}
+	bidder := newTestBidder(t, `{"floorCurrency":"USD"}`)
+
+	for call := 1; call <= 2; call++ {
+		rates := &countingRates{Rates: currency.NewRates(map[string]map[string]float64{
+			"EUR": {"USD": 1.25},
+			"GBP": {"USD": 1.5},
+		})}
+		reqInfo := adapters.NewExtraRequestInfo(rates)
+		if _, errs := bidder.MakeRequests(request, &reqInfo); len(errs) != 0 {
+			t.Fatalf("call %d: unexpected errors: %v", call, errs)
+		}
+		// One lookup each for EUR and GBP; USD floors need no rate
+		if rates.lookups != 2 {
+			t.Errorf("call %d: %d rate lookups, want 2", call, rates.lookups)
+		}
+	}
+}
+
+func TestMakeRequestsDeviceTypeRouting(t *testing.T) {
+	bidder := newTestBidder(t, `{"deviceTypeEndpoints":{"3":"https://ctv.example.com/bid"}}`)
+
+	tests := []struct {
+		name       string
+		device     *openrtb2.Device
+		wantTarget string
+	}{
+		{name: "connected TV", device: &openrtb2.Device{DeviceType: adcom1.DeviceTV}, wantTarget: "https://ctv.example.com/bid"},
+		{name: "mobile", device: &openrtb2.Device{DeviceType: adcom1.DeviceMobile}, wantTarget: "https://example.com/bid"},
+		{name: "no device", wantTarget: "https://example.com/bid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:     "test-request",
+				Device: tt.device,
+				Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+
+			reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(reqs) != 1 || reqs[0].Uri != tt.wantTarget {
+				t.Errorf("expected 1 request to %s, got %+v", tt.wantTarget, reqs)
+			}
+		})
+	}
+}
+
+func TestMakeBidsProtectedAudience(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"ae":1,"bidder":{}}`)}},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var sent struct {
+		Imp []struct {
+			Ext struct {
+				AE int `json:"ae"`
+			} `json:"ext"`
+		} `json:"imp"`
+	}
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if sent.Imp[0].Ext.AE != 1 {
+		t.Errorf("imp.ext.ae not forwarded, got %d", sent.Imp[0].Ext.AE)
+	}
+
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[],"ext":{"fledge_auction_configs":{` +
+			`"imp-1":{"seller":"https://seller.example.com","decisionLogicUrl":"https://seller.example.com/decide.js"},` +
+			`"imp-9":{"seller":"https://seller.example.com"}}}}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, reqs[0], response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), "imp-9") {
+		t.Errorf("expected a Warning naming imp-9, got %T %v", errs[0], errs[0])
+	}
+	if len(bidResponse.FledgeAuctionConfigs) != 1 {
+		t.Fatalf("expected 1 fledge auction config, got %d", len(bidResponse.FledgeAuctionConfigs))
+	}
+	auctionConfig := bidResponse.FledgeAuctionConfigs[0]
+	if auctionConfig.ImpId != "imp-1" || !strings.Contains(string(auctionConfig.Config), "decide.js") {
+		t.Errorf("unexpected fledge auction config %s: %s", auctionConfig.ImpId, auctionConfig.Config)
+	}
+}
+
+func TestMakeBidsMinBidPrice(t *testing.T) {
+	bidder := newTestBidder(t, `{"minBidPrice":0.01}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[` +
+			`{"id":"bid-1","impid":"imp-1","price":0.001},` +
+			`{"id":"bid-2","impid":"imp-1","price":0.01}]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), "bid-1") {
+		t.Errorf("expected a Warning naming bid-1, got %T %v", errs[0], errs[0])
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-2" {
+		t.Errorf("expected only bid-2 to be kept, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestMakeRequestsUserAgent(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		want      string
+	}{
+		{name: "unset by default", want: ""},
+		{name: "configured", extraInfo: `{"userAgent":"prebid-server/{{NAME}}"}`, want: "prebid-server/{{NAME}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := reqs[0].Headers.Get("User-Agent"); got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsMetrics(t *testing.T) {
+	metrics := []openrtb2.Metric{
+		{Type: "viewability", Value: 0.85, Vendor: "measurer.example"},
+		{Type: "viewability", Value: 85},
+		{Value: 1},
+	}
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Metric: metrics, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	tests := []struct {
+		name         string
+		extraInfo    string
+		wantWarnings int
+	}{
+		{name: "forwarded without validation", wantWarnings: 0},
+		{name: "validated", extraInfo: `{"validateMetrics":true}`, wantWarnings: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != tt.wantWarnings {
+				t.Fatalf("expected %d warnings, got %v", tt.wantWarnings, errs)
+			}
+			for _, err := range errs {
+				if _, ok := err.(*errortypes.Warning); !ok {
+					t.Errorf("expected *errortypes.Warning, got %T", err)
+				}
+			}
+
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			sameMetric := func(a, b openrtb2.Metric) bool {
+				return a.Type == b.Type && a.Value == b.Value && a.Vendor == b.Vendor
+			}
+			if !slices.EqualFunc(sent.Imp[0].Metric, metrics, sameMetric) {
+				t.Errorf("metrics not preserved, got %+v", sent.Imp[0].Metric)
+			}
+		})
+	}
+}
+
+func TestMakeBidsValidateVAST(t *testing.T) {
+	bidder := newTestBidder(t, `{"validateVast":true}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}}},
+	}
+
+	tests := []struct {
+		name    string
+		adm     string
+		wantBid bool
+	}{
+		{name: "valid", adm: `<VAST version="4.2"><Ad id="1"><InLine></InLine></Ad></VAST>`, wantBid: true},
+		{name: "valid with namespaces", adm: `<?xml version="1.0"?><VAST xmlns="http://www.iab.com/VAST" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" version="4.2"><Ad/></VAST>`, wantBid: true},
+		{name: "unclosed element", adm: `<VAST version="4.2"><Ad id="1"><InLine></Ad></VAST>`},
+		{name: "truncated", adm: `<VAST version="4.2"><Ad id="1">`},
+		{name: "not VAST", adm: `<div>banner</div>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bid, _ := json.Marshal(map[string]any{"id": "bid-1", "impid": "imp-1", "price": 1, "mtype": 2, "adm": tt.adm})
+			response := &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"id":"test-request","seatbid":[{"bid":[` + string(bid) + `]}]}`),
+			}
+
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+			if tt.wantBid {
+				if len(errs) != 0 || len(bidResponse.Bids) != 1 {
+					t.Errorf("expected the bid to be kept, got %d bids and errors %v", len(bidResponse.Bids), errs)
+				}
+				return
+			}
+			if len(bidResponse.Bids) != 0 {
+				t.Errorf("expected the bid to be dropped, got %d bids", len(bidResponse.Bids))
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if _, ok := errs[0].(*errortypes.Warning); !ok {
+				t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+			}
+		})
+	}
+}
+
+func TestMakeRequestsSplitTMax(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:   "test-request",
+		TMax: 900,
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Instl: 1, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-3", Banner: &openrtb2.Banner{}, Rwdd: 1, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		extraInfo  string
+		wantTMax   int64
+		wantHeader string
+	}{
+		{name: "full budget by default", extraInfo: `{"interstitialEndpoint":"https://example.com/interstitial","rewardedEndpoint":"https://example.com/rewarded"}`, wantTMax: 900},
+		{name: "divided across split requests", extraInfo: `{"splitTmax":true,"interstitialEndpoint":"https://example.com/interstitial","rewardedEndpoint":"https://example.com/rewarded"}`, wantTMax: 300, wantHeader: "300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(reqs) != 3 {
+				t.Fatalf("expected 3 requests, got %d", len(reqs))
+			}
+			for _, req := range reqs {
+				var sent openrtb2.BidRequest
+				if err := json.Unmarshal(req.Body, &sent); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				if sent.TMax != tt.wantTMax {
+					t.Errorf("%s: tmax = %d, want %d", req.Uri, sent.TMax, tt.wantTMax)
+				}
+				if got := req.Headers.Get(TMaxBudgetHeader); got != tt.wantHeader {
+					t.Errorf("%s: %s = %q, want %q", req.Uri, TMaxBudgetHeader, got, tt.wantHeader)
+				}
+			}
+		})
+	}
+
+	// A single request keeps the whole budget
+	reqs, _ := newTestBidder(t, `{"splitTmax":true}`).MakeRequests(&openrtb2.BidRequest{ID: "test-request", TMax: 900, Imp: request.Imp[:1]}, &adapters.ExtraRequestInfo{})
+	if got := reqs[0].Headers.Get(TMaxBudgetHeader); got != "" {
+		t.Errorf("unsplit request got %s %q", TMaxBudgetHeader, got)
+	}
+}
+
+func TestMakeRequestsAudio(t *testing.T) {
+	bidder := newTestBidder(t, `{"audioProtocols":[7,9]}`)
+	audio := &openrtb2.Audio{
+		MIMEs:         []string{"audio/mp4"},
+		Protocols:     []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30, adcom1.CreativeDAAST10},
+		Feed:          adcom1.FeedPodcast,
+		CompanionType: []adcom1.CompanionType{adcom1.CompanionStatic, adcom1.CompanionHTML},
+		CompanionAd:   []openrtb2.Banner{{ID: "companion-1", Format: []openrtb2.Format{{W: 300, H: 250}}}},
+	}
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Audio: audio, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-2", Audio: &openrtb2.Audio{MIMEs: []string{"audio/mp4"}, Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST20}}, Ext: json.RawMessage(`{"bidder":{}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.BadInput); !ok || !strings.Contains(errs[0].Error(), "imp-2") {
+		t.Errorf("expected a BadInput for imp-2, got %T %v", errs[0], errs[0])
+	}
+
+	var sent openrtb2.BidRequest
+	if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if len(sent.Imp) != 1 || sent.Imp[0].Audio == nil {
+		t.Fatalf("expected only imp-1 to be sent, got %+v", sent.Imp)
+	}
+	got := sent.Imp[0].Audio
+	if !slices.Equal(got.Protocols, []adcom1.MediaCreativeSubtype{adcom1.CreativeDAAST10}) {
+		t.Errorf("protocols = %v, want only DAAST 1.0", got.Protocols)
+	}
+	if got.Feed != adcom1.FeedPodcast || !slices.Equal(got.CompanionType, audio.CompanionType) {
+		t.Errorf("feed/companiontype not forwarded, got %v %v", got.Feed, got.CompanionType)
+	}
+	if len(got.CompanionAd) != 1 || got.CompanionAd[0].ID != "companion-1" {
+		t.Errorf("companionad not forwarded, got %+v", got.CompanionAd)
+	}
+	if len(audio.Protocols) != 2 {
+		t.Errorf("the core's audio object was modified: %v", audio.Protocols)
+	}
+}
+
+func TestMakeBidsStatusMapping(t *testing.T) {
+	bidder := newTestBidder(t, `{"statusMapping":{"299":204,"422":400}}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name      string
+		status    int
+		wantErr   error
+		wantNoBid bool
+	}{
+		{name: "mapped to no content", status: 299, wantNoBid: true},
+		{name: "mapped to bad request", status: 422, wantErr: &errortypes.BadInput{}},
+		{name: "standard codes unchanged", status: http.StatusBadRequest, wantErr: &errortypes.BadInput{}},
+		{name: "unmapped codes unchanged", status: 418, wantErr: &errortypes.BadServerResponse{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: tt.status})
+			if bidResponse != nil {
+				t.Errorf("expected a nil bid response, got %+v", bidResponse)
+			}
+			if tt.wantNoBid {
+				if len(errs) != 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if fmt.Sprintf("%T", errs[0]) != fmt.Sprintf("%T", tt.wantErr) {
+				t.Errorf("expected %T, got %T", tt.wantErr, errs[0])
+			}
+		})
+	}
+}
+
+func TestBuilderInvalidStatusMapping(t *testing.T) {
+	_, buildErr := Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid", ExtraAdapterInfo: `{"statusMapping":{"299":999}}`},
+		config.Server{},
+	)
+	if buildErr == nil {
+		t.Fatal("expected an error for a mapping to an unknown status")
+	}
+}
+
+func TestMakeRequestsSourceFD(t *testing.T) {
+	upstream, exchange := int8(1), int8(0)
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		source    *openrtb2.Source
+		want      *int8
+	}{
+		{name: "preserved", source: &openrtb2.Source{FD: &upstream, TID: "tid-1"}, want: &upstream},
+		{name: "absent without a default"},
+		{name: "defaulted", extraInfo: `{"defaultSourceFd":1}`, want: &upstream},
+		{name: "defaulted on an existing source", extraInfo: `{"defaultSourceFd":1}`, source: &openrtb2.Source{TID: "tid-1"}, want: &upstream},
+		{name: "request wins over default", extraInfo: `{"defaultSourceFd":1}`, source: &openrtb2.Source{FD: &exchange}, want: &exchange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:     "test-request",
+				Source: tt.source,
+				Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+
+			var got *int8
+			if sent.Source != nil {
+				got = sent.Source.FD
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("source.fd = %v, want %v", got, tt.want)
+			}
+			if tt.source != nil && tt.source.TID != "" && sent.Source.TID != tt.source.TID {
+				t.Errorf("source.tid = %q, want %q", sent.Source.TID, tt.source.TID)
+			}
+			if tt.source != nil && tt.source.FD == nil && request.Source.FD != nil {
+				t.Error("the core's source was modified")
+			}
+		})
+	}
+}
+
+func TestMakeBidsNoBidReason(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		wantWarning string
+	}{
+		{name: "ext.nbr", body: `{"id":"test-request","seatbid":[],"ext":{"nbr":8}}`, wantWarning: "nbr 8 (unmatched user)"},
+		{name: "top-level nbr", body: `{"id":"test-request","nbr":2}`, wantWarning: "nbr 2 (invalid request)"},
+		{name: "endpoint-specific code", body: `{"id":"test-request","ext":{"nbr":501}}`, wantWarning: "nbr 501 (endpoint-specific)"},
+		{name: "no reason given", body: `{"id":"test-request","seatbid":[]}`},
+		{name: "ignored when bids returned", body: `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-1","impid":"imp-1","price":1}]}],"ext":{"nbr":8}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := bidder.MakeBids(request, &adapters.RequestData{}, &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(tt.body)})
+			if tt.wantWarning == "" {
+				if len(errs) != 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 warning, got %v", errs)
+			}
+			if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), tt.wantWarning) {
+				t.Errorf("expected a Warning containing %q, got %T %v", tt.wantWarning, errs[0], errs[0])
+			}
+		})
+	}
+}
+
+// The core merges stored imps and rebuilds imp.ext without
+// prebid.storedrequest before adapters run; a leftover reference is forwarded
+// untouched rather than rejected
+func TestMakeRequestsResidualStoredRequest(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	request := &openrtb2.BidRequest{
+		ID: "test-request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"prebid":{"storedrequest":{"id":"stored-1"}},"bidder":{"placementId":"123"}}`)},
+			{ID: "imp-2", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":"456"}}`)},
+		},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqs) != 1 || !slices.Equal(reqs[0].ImpIDs, []string{"imp-1", "imp-2"}) {
+		t.Errorf("expected both imps to be sent, got %+v", reqs)
+	}
+}
+
+func TestMakeBidsDedupBids(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[` +
+			`{"seat":"seat-a","bid":[{"id":"bid-1","impid":"imp-1","price":1},{"id":"bid-2","impid":"imp-1","price":0.5}]},` +
+			`{"seat":"seat-b","bid":[{"id":"bid-1","impid":"imp-1","price":1.5,"crid":"higher"}]}]}`),
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		bidResponse, errs := newTestBidder(t, "").MakeBids(request, &adapters.RequestData{}, response)
+		if len(errs) != 0 || len(bidResponse.Bids) != 3 {
+			t.Errorf("expected all 3 bids, got %d and errors %v", len(bidResponse.Bids), errs)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		bidResponse, errs := newTestBidder(t, `{"dedupBids":true}`).MakeBids(request, &adapters.RequestData{}, response)
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 warning, got %v", errs)
+		}
+		if _, ok := errs[0].(*errortypes.Warning); !ok {
+			t.Errorf("expected *errortypes.Warning, got %T", errs[0])
+		}
+		if len(bidResponse.Bids) != 2 {
+			t.Fatalf("expected 2 bids, got %d", len(bidResponse.Bids))
+		}
+		if bid := bidResponse.Bids[0].Bid; bid.ID != "bid-1" || bid.Price != 1.5 || bid.CrID != "higher" {
+			t.Errorf("expected the higher-priced bid-1 first, got %+v", bid)
+		}
+		if bid := bidResponse.Bids[1].Bid; bid.ID != "bid-2" {
+			t.Errorf("expected bid-2 second, got %+v", bid)
+		}
+	})
+}
+
+func TestMakeRequestsNativeRegs(t *testing.T) {
+	gdprApplies, gdprNotApplies := int8(1), int8(0)
+
+	tests := []struct {
+		name          string
+		extraInfo     string
+		regs          *openrtb2.Regs
+		wantGDPR      *int8
+		wantUSPrivacy string
+	}{
+		{name: "native", extraInfo: `{"nativeRegs":true}`, regs: &openrtb2.Regs{GDPR: &gdprApplies, USPrivacy: "1YNN"}, wantGDPR: &gdprApplies, wantUSPrivacy: "1YNN"},
+		{name: "legacy ext promoted", extraInfo: `{"nativeRegs":true}`, regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gdpr":1,"us_privacy":"1YNN"}`)}, wantGDPR: &gdprApplies, wantUSPrivacy: "1YNN"},
+		{name: "native wins over ext", extraInfo: `{"nativeRegs":true}`, regs: &openrtb2.Regs{GDPR: &gdprNotApplies, USPrivacy: "1NNN", Ext: json.RawMessage(`{"gdpr":1,"us_privacy":"1YNN"}`)}, wantGDPR: &gdprNotApplies, wantUSPrivacy: "1NNN"},
+		{name: "legacy ext left alone when disabled", regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gdpr":1}`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:   "test-request",
+				Regs: tt.regs,
+				Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+			original := *tt.regs
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var sent openrtb2.BidRequest
+			if err := json.Unmarshal(reqs[0].Body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+
+			got := sent.Regs.GDPR
+			if (got == nil) != (tt.wantGDPR == nil) || (got != nil && *got != *tt.wantGDPR) {
+				t.Errorf("regs.gdpr = %v, want %v", got, tt.wantGDPR)
+			}
+			if sent.Regs.USPrivacy != tt.wantUSPrivacy {
+				t.Errorf("regs.us_privacy = %q, want %q", sent.Regs.USPrivacy, tt.wantUSPrivacy)
+			}
+			if request.Regs.GDPR != original.GDPR || request.Regs.USPrivacy != original.USPrivacy {
+				t.Error("the core's regs were modified")
+			}
+		})
+	}
+}
+
+func TestMakeRequestsRequestIDHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		extraInfo string
+		id        string
+		source    *openrtb2.Source
+		want      string
+	}{
+		{name: "unset by default", id: "test-request", want: ""},
+		{name: "request id", extraInfo: `{"requestIdHeader":true}`, id: "test-request", source: &openrtb2.Source{TID: "tid-1"}, want: "test-request"},
+		{name: "source tid fallback", extraInfo: `{"requestIdHeader":true}`, source: &openrtb2.Source{TID: "tid-1"}, want: "tid-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:     tt.id,
+				Source: tt.source,
+				Imp:    []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+			}
+
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := reqs[0].Headers.Get(RequestIDHeader); got != tt.want {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeBidsMaxAdmBytes(t *testing.T) {
+	bidder := newTestBidder(t, `{"maxAdmBytes":16}`)
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[{"bid":[` +
+			`{"id":"bid-1","impid":"imp-1","price":1,"adm":"<div>` + strings.Repeat("x", 64) + `</div>"},` +
+			`{"id":"bid-2","impid":"imp-1","price":1,"adm":"<div>ad</div>"}]}]}`),
+	}
+
+	bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), "bid-1") {
+		t.Errorf("expected a Warning naming bid-1, got %T %v", errs[0], errs[0])
+	}
+	if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != "bid-2" {
+		t.Errorf("expected only bid-2 to be kept, got %+v", bidResponse.Bids)
+	}
+}
+
+func TestMakeBidsAlternateBidderCodes(t *testing.T) {
+	bidder := newTestBidder(t, "")
+	response := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body: []byte(`{"id":"test-request","seatbid":[` +
+			`{"seat":"{{NAME_LOWER}}","bid":[{"id":"bid-1","impid":"imp-1","price":1}]},` +
+			`{"seat":"partner-a","bid":[{"id":"bid-2","impid":"imp-1","price":2}]},` +
+			`{"seat":"partner-b","bid":[{"id":"bid-3","impid":"imp-1","price":3}]}]}`),
+	}
+
+	tests := []struct {
+		name        string
+		ext         string
+		wantSeats   map[string]openrtb_ext.BidderName
+		wantWarning string
+	}{
+		{
+			name:      "seats untagged without alternatebiddercodes",
+			wantSeats: map[string]openrtb_ext.BidderName{"bid-1": "", "bid-2": "", "bid-3": ""},
+		},
+		{
+			name:        "allowlisted alternate seat tagged",
+			ext:         `{"prebid":{"alternatebiddercodes":{"enabled":true,"bidders":{"{{NAME_LOWER}}":{"enabled":true,"allowedbiddercodes":["partner-a"]}}}}}`,
+			wantSeats:   map[string]openrtb_ext.BidderName{"bid-1": "", "bid-2": "partner-a", "bid-3": ""},
+			wantWarning: "partner-b",
+		},
+		{
+			name:      "any alternate seat tagged without an allowlist",
+			ext:       `{"prebid":{"alternatebiddercodes":{"enabled":true,"bidders":{"{{NAME_LOWER}}":{"enabled":true}}}}}`,
+			wantSeats: map[string]openrtb_ext.BidderName{"bid-1": "", "bid-2": "partner-a", "bid-3": "partner-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{
+				ID:  "test-request",
+				Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+			}
+			if tt.ext != "" {
+				request.Ext = json.RawMessage(tt.ext)
+			}
+
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+			if tt.wantWarning == "" && len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if tt.wantWarning != "" {
+				if len(errs) != 1 {
+					t.Fatalf("expected 1 error, got %v", errs)
+				}
+				if _, ok := errs[0].(*errortypes.Warning); !ok || !strings.Contains(errs[0].Error(), tt.wantWarning) {
+					t.Errorf("expected a Warning naming %s, got %T %v", tt.wantWarning, errs[0], errs[0])
+				}
+			}
+
+			got := make(map[string]openrtb_ext.BidderName)
+			gotMeta := make(map[string]string)
+			for _, typedBid := range bidResponse.Bids {
+				got[typedBid.Bid.ID] = typedBid.Seat
+				if typedBid.BidMeta != nil {
+					gotMeta[typedBid.Bid.ID] = typedBid.BidMeta.Seat
+				}
+			}
+			if !maps.Equal(got, tt.wantSeats) {
+				t.Errorf("bid seats = %v, want %v", got, tt.wantSeats)
+			}
+			// the DSP seat survives in meta whether or not the bids are tagged with it
+			wantMeta := map[string]string{"bid-1": "{{NAME_LOWER}}", "bid-2": "partner-a", "bid-3": "partner-b"}
+			if !maps.Equal(gotMeta, wantMeta) {
+				t.Errorf("meta seats = %v, want %v", gotMeta, wantMeta)
+			}
+		})
+	}
+}
+
+func TestMakeRequestsAccept(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+	}
+
+	tests := []struct {
+		name      string
+		extraInfo string
+		want      string
+	}{
+		{name: "json by default", want: "application/json"},
+		{name: "configured", extraInfo: `{"accept":"application/x-protobuf, application/json;q=0.5"}`, want: "application/x-protobuf, application/json;q=0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, errs := newTestBidder(t, tt.extraInfo).MakeRequests(request, &adapters.ExtraRequestInfo{})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := reqs[0].Headers.Get("Accept"); got != tt.want {
+				t.Errorf("Accept = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeBidsResponseDecoders(t *testing.T) {
+	decoders := map[string]ResponseDecoder{"Application/X-Test": func(body []byte) (openrtb2.BidResponse, error) {
+		id, price, _ := strings.Cut(string(body), ":")
+		value, err := strconv.ParseFloat(price, 64)
+		if err != nil {
+			return openrtb2.BidResponse{}, err
+		}
+		return openrtb2.BidResponse{ID: "test-request", SeatBid: []openrtb2.SeatBid{{
+			Bid: []openrtb2.Bid{{ID: id, ImpID: "imp-1", Price: value}},
+		}}}, nil
+	}}
+	bidder, buildErr := DecoderBuilder(decoders)(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: "https://example.com/bid"},
+		config.Server{},
+	)
+	if buildErr != nil {
+		t.Fatalf("DecoderBuilder returned unexpected error: %v", buildErr)
+	}
+	request := &openrtb2.BidRequest{
+		ID:  "test-request",
+		Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantBidID   string
+		wantErr     bool
+	}{
+		{name: "registered decoder", contentType: "application/x-test; charset=binary", body: "bid-1:1.5", wantBidID: "bid-1"},
+		{name: "json", contentType: "application/json;charset=utf-8", body: `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-2","impid":"imp-1","price":1}]}]}`, wantBidID: "bid-2"},
+		{name: "json without content type", body: `{"id":"test-request","seatbid":[{"bid":[{"id":"bid-3","impid":"imp-1","price":1}]}]}`, wantBidID: "bid-3"},
+		{name: "registered decoder error", contentType: "application/x-test", body: "bid-1:price", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Headers:    http.Header{},
+				Body:       []byte(tt.body),
+			}
+			if tt.contentType != "" {
+				response.Headers.Set("Content-Type", tt.contentType)
+			}
+
+			bidResponse, errs := bidder.MakeBids(request, &adapters.RequestData{}, response)
+			if tt.wantErr {
+				if len(errs) != 1 {
+					t.Fatalf("expected 1 error, got %v", errs)
+				}
+				if _, ok := errs[0].(*errortypes.BadServerResponse); !ok {
+					t.Errorf("expected a BadServerResponse, got %T %v", errs[0], errs[0])
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(bidResponse.Bids) != 1 || bidResponse.Bids[0].Bid.ID != tt.wantBidID {
+				t.Errorf("expected bid %s, got %+v", tt.wantBidID, bidResponse.Bids)
+			}
+		})
+	}
 }
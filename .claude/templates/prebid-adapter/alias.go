@@ -0,0 +1,19 @@
+package {{NAME_LOWER}}
+
+import (
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// AliasBuilder returns a Builder for aliases of {{NAME}} that share one endpoint.
+// Register it under each alias name in exchange/adapter_builders.go; an alias
+// with its own endpoint configured keeps it.
+func AliasBuilder(sharedEndpoint string) adapters.Builder {
+	return func(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+		if config.Endpoint == "" {
+			config.Endpoint = sharedEndpoint
+		}
+		return Builder(bidderName, config, server)
+	}
+}
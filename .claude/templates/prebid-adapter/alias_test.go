@@ -0,0 +1,39 @@
+package {{NAME_LOWER}}
+
+import (
+	"testing"
+
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+func TestAliasBuilder(t *testing.T) {
+	build := AliasBuilder("https://shared.example.com/bid")
+
+	tests := []struct {
+		name     string
+		alias    openrtb_ext.BidderName
+		endpoint string
+		want     string
+	}{
+		{name: "shared endpoint", alias: "{{NAME_LOWER}}alias", want: "https://shared.example.com/bid"},
+		{name: "own endpoint", alias: "{{NAME_LOWER}}other", endpoint: "https://other.example.com/bid", want: "https://other.example.com/bid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidder, buildErr := build(tt.alias, config.Adapter{Endpoint: tt.endpoint}, config.Server{})
+			if buildErr != nil {
+				t.Fatalf("Builder returned unexpected error: %v", buildErr)
+			}
+
+			a := bidder.(*adapter)
+			if a.bidderName != tt.alias {
+				t.Errorf("bidderName = %s, want %s", a.bidderName, tt.alias)
+			}
+			if a.endpoint != tt.want {
+				t.Errorf("endpoint = %s, want %s", a.endpoint, tt.want)
+			}
+		})
+	}
+}
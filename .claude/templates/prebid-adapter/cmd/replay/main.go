@@ -0,0 +1,109 @@
+// Command replay runs a saved BidRequest through the {{NAME}} adapter and
+// prints the outgoing HTTP requests, for debugging an integration without a
+// running Prebid Server.
+//
+//	go run ./adapters/{{NAME_LOWER}}/cmd/replay -request saved.json
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/adapters/{{NAME_LOWER}}"
+	"{{PBS_MODULE}}/config"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+func main() {
+	requestFile := flag.String("request", "-", "BidRequest JSON file, - for stdin")
+	endpoint := flag.String("endpoint", "https://example.com/bid", "endpoint from static/bidder-info/{{NAME_LOWER}}.yaml")
+	extraInfo := flag.String("extra-info", "", "extra_info JSON passed to the Builder")
+	flag.Parse()
+
+	if err := replay(os.Stdout, *requestFile, *endpoint, *extraInfo); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func replay(w io.Writer, requestFile, endpoint, extraInfo string) error {
+	body, err := readRequest(requestFile)
+	if err != nil {
+		return err
+	}
+	var request openrtb2.BidRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return fmt.Errorf("decode BidRequest: %w", err)
+	}
+
+	bidder, err := {{NAME_LOWER}}.Builder(
+		openrtb_ext.Bidder{{NAME}},
+		config.Adapter{Endpoint: endpoint, ExtraAdapterInfo: extraInfo},
+		config.Server{},
+	)
+	if err != nil {
+		return fmt.Errorf("build adapter: %w", err)
+	}
+
+	reqs, errs := bidder.MakeRequests(&request, &adapters.ExtraRequestInfo{})
+	for _, err := range errs {
+		fmt.Fprintf(w, "error: %v\n", err)
+	}
+	for _, req := range reqs {
+		if err := printRequest(w, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRequest(requestFile string) ([]byte, error) {
+	if requestFile == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(requestFile)
+}
+
+// printRequest writes req as it would go over the wire, with a gzip body
+// decompressed and JSON indented so it can be read
+func printRequest(w io.Writer, req *adapters.RequestData) error {
+	fmt.Fprintf(w, "\n%s %s\n", req.Method, req.Uri)
+
+	names := make([]string, 0, len(req.Headers))
+	for name := range req.Headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range req.Headers[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+
+	body := req.Body
+	if req.Headers.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("decompress body: %w", err)
+		}
+		if body, err = io.ReadAll(zr); err != nil {
+			return fmt.Errorf("decompress body: %w", err)
+		}
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		indented.Reset()
+		indented.Write(body)
+	}
+	fmt.Fprintf(w, "\n%s\n", indented.String())
+	return nil
+}
@@ -0,0 +1,10 @@
+package {{NAME_LOWER}}
+
+// adapterConfig holds the endpoint settings declared with --config-field when
+// the adapter was generated. Builder reads them from the "config" object of
+// extra_info, e.g. {"config":{"host":"bid.example.com"}}, into
+// a.extraInfo.Config; the template does not send them anywhere, so read each
+// one where the endpoint needs it.
+type adapterConfig struct {
+	{{CONFIG_FIELDS}}
+}
@@ -0,0 +1,4 @@
+package {{NAME_LOWER}}
+
+// adapterConfig is empty: the adapter was generated without --config-field
+type adapterConfig struct{}
@@ -0,0 +1,44 @@
+package {{NAME_LOWER}}
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestAdapterConfigFields fills every --config-field declared at generation
+// from the config object of extra_info, whatever fields were chosen
+func TestAdapterConfigFields(t *testing.T) {
+	samples := map[reflect.Kind]any{
+		reflect.String:  "value",
+		reflect.Bool:    true,
+		reflect.Int:     7,
+		reflect.Int64:   7,
+		reflect.Float64: 1.5,
+		reflect.Slice:   []string{"value"},
+		reflect.Map:     map[string]string{"key": "value"},
+	}
+
+	cfgType := reflect.TypeOf(adapterConfig{})
+	values := make(map[string]any, cfgType.NumField())
+	for i := 0; i < cfgType.NumField(); i++ {
+		field := cfgType.Field(i)
+		sample, ok := samples[field.Type.Kind()]
+		if !ok {
+			t.Fatalf("no sample value for field %s of type %s", field.Name, field.Type)
+		}
+		values[field.Tag.Get("json")] = sample
+	}
+	extraInfo, err := json.Marshal(map[string]any{"config": values})
+	if err != nil {
+		t.Fatalf("failed to encode config: %v", err)
+	}
+
+	cfg := newTestBidder(t, string(extraInfo)).(*adapter).extraInfo.Config
+	got := reflect.ValueOf(cfg)
+	for i := 0; i < cfgType.NumField(); i++ {
+		if got.Field(i).IsZero() {
+			t.Errorf("field %s was not filled from %s", cfgType.Field(i).Name, extraInfo)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package {{NAME_LOWER}}
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// paramDefaults holds the bidder param defaults declared with --param-default
+// when the adapter was generated, as params JSON. Empty declares none.
+const paramDefaults = {{PARAM_DEFAULTS}}
+
+// checkParamDefaults fails Builder when paramDefaults no longer decodes, for
+// instance after a param was renamed
+func checkParamDefaults() error {
+	if paramDefaults == "" {
+		return nil
+	}
+	var ext openrtb_ext.ExtImp{{NAME}}
+	if err := json.Unmarshal([]byte(paramDefaults), &ext); err != nil {
+		return fmt.Errorf("invalid param defaults for {{NAME}}: %w", err)
+	}
+	return nil
+}
+
+// decodeParams decodes an imp's bidder params over paramDefaults, so params
+// the imp omits keep their default and params it sets always win
+func decodeParams(bidder json.RawMessage, ext *openrtb_ext.ExtImp{{NAME}}) error {
+	if paramDefaults != "" {
+		if err := json.Unmarshal([]byte(paramDefaults), ext); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(bidder, ext)
+}
@@ -0,0 +1,42 @@
+package {{NAME_LOWER}}
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+func TestCheckParamDefaults(t *testing.T) {
+	if err := checkParamDefaults(); err != nil {
+		t.Fatalf("generated param defaults do not decode: %v", err)
+	}
+}
+
+func TestDecodeParams(t *testing.T) {
+	var defaults openrtb_ext.ExtImp{{NAME}}
+	if paramDefaults != "" {
+		if err := json.Unmarshal([]byte(paramDefaults), &defaults); err != nil {
+			t.Fatalf("failed to decode param defaults: %v", err)
+		}
+	}
+
+	var omitted openrtb_ext.ExtImp{{NAME}}
+	if err := decodeParams(json.RawMessage(`{"placementId":"p-1"}`), &omitted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := defaults
+	want.PlacementID = "p-1"
+	if !reflect.DeepEqual(omitted, want) {
+		t.Errorf("omitted params = %+v, want the defaults %+v", omitted, want)
+	}
+
+	var set openrtb_ext.ExtImp{{NAME}}
+	if err := decodeParams(json.RawMessage(`{"placementId":"p-1","siteId":"site-from-imp"}`), &set); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if set.SiteID != "site-from-imp" {
+		t.Errorf("siteId = %q, want the imp's own value", set.SiteID)
+	}
+}
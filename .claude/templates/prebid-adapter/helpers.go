@@ -0,0 +1,29 @@
+package {{NAME_LOWER}}
+
+import (
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/adapters/internal/lughhelpers"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// The adapter converts floors and builds bid meta with the shared lughhelpers
+// package. Adapters generated without --with-helpers get the same functions
+// from their own helpers_local.go.
+
+type rateCache = lughhelpers.RateCache
+
+// newRateCache returns a cache over reqInfo's conversions for one MakeRequests call
+func newRateCache(reqInfo *adapters.ExtraRequestInfo) *rateCache {
+	return lughhelpers.NewRateCache(reqInfo)
+}
+
+// convertFloor returns imp.bidfloor in currency
+func convertFloor(rates *rateCache, imp *openrtb2.Imp, currency string) (float64, error) {
+	return lughhelpers.ConvertFloor(rates, imp, currency)
+}
+
+// baseBidMeta builds the prebid meta the core reads from bid.adomain and bid.cat
+func baseBidMeta(bid *openrtb2.Bid) *openrtb_ext.ExtBidPrebidMeta {
+	return lughhelpers.BidMeta(bid)
+}
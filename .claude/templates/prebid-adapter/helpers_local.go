@@ -0,0 +1,65 @@
+package {{NAME_LOWER}}
+
+import (
+	"cmp"
+
+	"{{OPENRTB_MODULE}}/openrtb2"
+	"{{PBS_MODULE}}/adapters"
+	"{{PBS_MODULE}}/openrtb_ext"
+)
+
+// This file is the adapter's own copy of the lughhelpers code, emitted when it
+// is generated without --with-helpers. Keep it in step with
+// templates/_shared/lughhelpers.
+
+// rateCache looks up each currency pair in the core's rates once. Create one
+// per MakeRequests call: rates can change between auctions.
+type rateCache struct {
+	reqInfo *adapters.ExtraRequestInfo
+	rates   map[[2]string]float64
+}
+
+// newRateCache returns an empty cache over reqInfo's conversions
+func newRateCache(reqInfo *adapters.ExtraRequestInfo) *rateCache {
+	return &rateCache{reqInfo: reqInfo, rates: make(map[[2]string]float64)}
+}
+
+// convert converts value between currencies. Failed lookups are not cached.
+func (c *rateCache) convert(value float64, from, to string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+	key := [2]string{from, to}
+	rate, ok := c.rates[key]
+	if !ok {
+		var err error
+		if rate, err = c.reqInfo.ConvertCurrency(1, from, to); err != nil {
+			return 0, err
+		}
+		c.rates[key] = rate
+	}
+	return value * rate, nil
+}
+
+// convertFloor returns imp.bidfloor in currency. bidfloorcur defaults to USD
+// as in OpenRTB, and a zero floor needs no rate.
+func convertFloor(rates *rateCache, imp *openrtb2.Imp, currency string) (float64, error) {
+	if imp.BidFloor == 0 {
+		return 0, nil
+	}
+	return rates.convert(imp.BidFloor, cmp.Or(imp.BidFloorCur, "USD"), currency)
+}
+
+// baseBidMeta builds the prebid meta the core reads from bid.adomain and
+// bid.cat, or returns nil when the bid carries neither
+func baseBidMeta(bid *openrtb2.Bid) *openrtb_ext.ExtBidPrebidMeta {
+	if len(bid.ADomain) == 0 && len(bid.Cat) == 0 {
+		return nil
+	}
+	meta := &openrtb_ext.ExtBidPrebidMeta{AdvertiserDomains: bid.ADomain}
+	if len(bid.Cat) > 0 {
+		meta.PrimaryCategoryID = bid.Cat[0]
+		meta.SecondaryCategoryIDs = bid.Cat[1:]
+	}
+	return meta
+}
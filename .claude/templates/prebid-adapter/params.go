@@ -1,12 +1,78 @@
 package openrtb_ext
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
 // ExtImp{{NAME}} defines the bidder params for {{NAME}}
 type ExtImp{{NAME}} struct {
 	// PlacementID is the placement identifier
 	PlacementID string `json:"placementId"`
-	
+
 	// SiteID is the site identifier (optional)
 	SiteID string `json:"siteId,omitempty"`
-	
+
+	// MediaTypes restricts the bid types accepted for the placement (optional)
+	MediaTypes []string `json:"mediaTypes,omitempty"`
+
+	// MinDuration and MaxDuration bound video creative length in seconds (optional)
+	MinDuration int64 `json:"minDuration,omitempty"`
+	MaxDuration int64 `json:"maxDuration,omitempty"`
+
 	// TODO: Add your bidder-specific parameters here
 }
+
+// Validate checks the param combinations the JSON schema cannot express
+func (ext *ExtImp{{NAME}}) Validate() error {
+	if ext.MinDuration < 0 || ext.MaxDuration < 0 {
+		return fmt.Errorf("minDuration and maxDuration must not be negative")
+	}
+	if ext.MaxDuration > 0 && ext.MinDuration > ext.MaxDuration {
+		return fmt.Errorf("minDuration %d is greater than maxDuration %d", ext.MinDuration, ext.MaxDuration)
+	}
+	return nil
+}
+
+// extImp{{NAME}}Aliases maps legacy param spellings to their canonical JSON tag
+var extImp{{NAME}}Aliases = map[string]string{
+	{{PARAM_ALIASES}}
+}
+
+// UnmarshalJSON accepts both the canonical and the legacy param spellings.
+// When both are present the canonical one wins. Legacy spellings are applied
+// in sorted order, so of two sharing a canonical param the first one sorted
+// wins.
+func (ext *ExtImp{{NAME}}) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	legacyNames := make([]string, 0, len(extImp{{NAME}}Aliases))
+	for legacy := range extImp{{NAME}}Aliases {
+		legacyNames = append(legacyNames, legacy)
+	}
+	sort.Strings(legacyNames)
+
+	for _, legacy := range legacyNames {
+		canonical := extImp{{NAME}}Aliases[legacy]
+		value, ok := fields[legacy]
+		if !ok {
+			continue
+		}
+		if _, exists := fields[canonical]; !exists {
+			fields[canonical] = value
+		}
+		delete(fields, legacy)
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	type plain ExtImp{{NAME}}
+	return json.Unmarshal(normalized, (*plain)(ext))
+}
@@ -0,0 +1,158 @@
+package openrtb_ext
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestExtImp{{NAME}}SchemaParity checks that static/bidder-params/{{NAME_LOWER}}.json
+// declares the ExtImp{{NAME}} params plus their legacy spellings from
+// extImp{{NAME}}Aliases, and that each tag without omitempty is required,
+// through an anyOf when it has legacy spellings.
+func TestExtImp{{NAME}}SchemaParity(t *testing.T) {
+	data, err := os.ReadFile("../static/bidder-params/{{NAME_LOWER}}.json")
+	if err != nil {
+		t.Fatalf("failed to read bidder params schema: %v", err)
+	}
+
+	type requiredAnyOf []struct {
+		Required []string `json:"required"`
+	}
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+		AnyOf      requiredAnyOf              `json:"anyOf"`
+		AllOf      []struct {
+			AnyOf requiredAnyOf `json:"anyOf"`
+		} `json:"allOf"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to parse bidder params schema: %v", err)
+	}
+
+	// A required group is a param and its legacy spellings, any one of which
+	// satisfies the schema
+	legacyNames := make(map[string][]string)
+	for legacy, canonical := range extImp{{NAME}}Aliases {
+		legacyNames[canonical] = append(legacyNames[canonical], legacy)
+	}
+	group := func(names []string) string {
+		sorted := slices.Clone(names)
+		sort.Strings(sorted)
+		return strings.Join(sorted, "|")
+	}
+
+	var structProps, structRequired []string
+	extType := reflect.TypeOf(ExtImp{{NAME}}{})
+	for i := 0; i < extType.NumField(); i++ {
+		name, opts, _ := strings.Cut(extType.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		structProps = append(structProps, name)
+		structProps = append(structProps, legacyNames[name]...)
+		if !strings.Contains(opts, "omitempty") {
+			structRequired = append(structRequired, group(append([]string{name}, legacyNames[name]...)))
+		}
+	}
+
+	var schemaProps, schemaRequired []string
+	for name := range schema.Properties {
+		schemaProps = append(schemaProps, name)
+	}
+	schemaRequired = append(schemaRequired, schema.Required...)
+	anyOfs := []requiredAnyOf{schema.AnyOf}
+	for _, all := range schema.AllOf {
+		anyOfs = append(anyOfs, all.AnyOf)
+	}
+	for _, anyOf := range anyOfs {
+		var names []string
+		for _, branch := range anyOf {
+			names = append(names, branch.Required...)
+		}
+		if len(names) > 0 {
+			schemaRequired = append(schemaRequired, group(names))
+		}
+	}
+
+	sort.Strings(structProps)
+	sort.Strings(structRequired)
+	sort.Strings(schemaProps)
+	sort.Strings(schemaRequired)
+
+	if !reflect.DeepEqual(schemaProps, structProps) {
+		t.Errorf("schema properties %v do not match struct tags and aliases %v", schemaProps, structProps)
+	}
+	if !reflect.DeepEqual(schemaRequired, structRequired) {
+		t.Errorf("schema required %v do not match non-omitempty tags %v", schemaRequired, structRequired)
+	}
+}
+
+func TestExtImp{{NAME}}ParamAliases(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{name: "canonical", json: `{"placementId":"123"}`, want: "123"},
+		{name: "legacy", json: `{"placement_id":"123"}`, want: "123"},
+		{name: "canonical wins", json: `{"placementId":"123","placement_id":"456"}`, want: "123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ext ExtImp{{NAME}}
+			if err := json.Unmarshal([]byte(tt.json), &ext); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ext.PlacementID != tt.want {
+				t.Errorf("PlacementID = %q, want %q", ext.PlacementID, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtImp{{NAME}}SharedParamAliases(t *testing.T) {
+	aliases := extImp{{NAME}}Aliases
+	extImp{{NAME}}Aliases = map[string]string{"placement_id": "placementId", "placement": "placementId"}
+	t.Cleanup(func() { extImp{{NAME}}Aliases = aliases })
+
+	// map iteration order is random, so repeat to catch an unordered pick
+	for i := 0; i < 50; i++ {
+		var ext ExtImp{{NAME}}
+		if err := json.Unmarshal([]byte(`{"placement_id":"123","placement":"456"}`), &ext); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ext.PlacementID != "456" {
+			t.Fatalf("PlacementID = %q, want the first legacy spelling sorted (placement)", ext.PlacementID)
+		}
+	}
+}
+
+func TestExtImp{{NAME}}Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     ExtImp{{NAME}}
+		wantErr bool
+	}{
+		{name: "no durations", ext: ExtImp{{NAME}}{}},
+		{name: "valid range", ext: ExtImp{{NAME}}{MinDuration: 5, MaxDuration: 30}},
+		{name: "min only", ext: ExtImp{{NAME}}{MinDuration: 5}},
+		{name: "min greater than max", ext: ExtImp{{NAME}}{MinDuration: 30, MaxDuration: 5}, wantErr: true},
+		{name: "negative min", ext: ExtImp{{NAME}}{MinDuration: -1}, wantErr: true},
+		{name: "negative max", ext: ExtImp{{NAME}}{MaxDuration: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ext.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package {{NAME_LOWER}}
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds a Ping made with the default client, so a hung endpoint
+// cannot block the health check
+const pingTimeout = 5 * time.Second
+
+// Ping checks that the configured endpoint is reachable. It is meant for ops
+// health checks and is never called during an auction. Any response below 500
+// counts as reachable, since auction endpoints often reject a bare GET. A nil
+// client uses one that gives up after pingTimeout; ctx can cut it shorter.
+func (a *adapter) Ping(ctx context.Context, client *http.Client) error {
+	if client == nil {
+		client = &http.Client{Timeout: pingTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("{{NAME}} ping: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("{{NAME}} ping: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("{{NAME}} ping: endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
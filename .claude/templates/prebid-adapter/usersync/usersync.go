@@ -0,0 +1,45 @@
+// Package {{NAME_LOWER}}usersync is a stub for integrations that need Go-side
+// user sync handling beyond the userSync entry in bidder-info.
+package {{NAME_LOWER}}usersync
+
+import (
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// SyncURL matches userSync.redirect.url in static/bidder-info/{{NAME_LOWER}}.yaml
+const SyncURL = "https://sync.example.com/{{NAME_LOWER}}?gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&us_privacy={{.USPrivacy}}&gpp={{.GPP}}&gpp_sid={{.GPPSID}}&redirect={{.RedirectURL}}"
+
+// UserMacro is replaced by the endpoint with its user ID in the redirect URL
+const UserMacro = "$UID"
+
+// Macros holds the privacy and redirect values PBS substitutes into SyncURL
+type Macros struct {
+	GDPR        string
+	GDPRConsent string
+	USPrivacy   string
+	GPP         string
+	GPPSID      string
+	RedirectURL string
+}
+
+var syncTemplate = template.Must(template.New("{{NAME_LOWER}}-sync").Parse(SyncURL))
+
+// BuildSyncURL renders SyncURL, query-escaping every macro value
+func BuildSyncURL(macros Macros) (string, error) {
+	escaped := Macros{
+		GDPR:        url.QueryEscape(macros.GDPR),
+		GDPRConsent: url.QueryEscape(macros.GDPRConsent),
+		USPrivacy:   url.QueryEscape(macros.USPrivacy),
+		GPP:         url.QueryEscape(macros.GPP),
+		GPPSID:      url.QueryEscape(macros.GPPSID),
+		RedirectURL: url.QueryEscape(macros.RedirectURL),
+	}
+
+	var b strings.Builder
+	if err := syncTemplate.Execute(&b, escaped); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
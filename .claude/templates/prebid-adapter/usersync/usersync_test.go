@@ -0,0 +1,30 @@
+package {{NAME_LOWER}}usersync
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestBuildSyncURL(t *testing.T) {
+	syncURL, err := BuildSyncURL(Macros{
+		GDPR:        "1",
+		GDPRConsent: "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+		GPPSID:      "2,6",
+		RedirectURL: "https://pbs.example.com/setuid?bidder={{NAME_LOWER}}&uid=" + UserMacro,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed, err := url.Parse(syncURL)
+	if err != nil {
+		t.Fatalf("sync URL does not parse: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("gdpr") != "1" || query.Get("gpp_sid") != "2,6" {
+		t.Errorf("privacy macros not substituted: %s", syncURL)
+	}
+	if got := query.Get("redirect"); got != "https://pbs.example.com/setuid?bidder={{NAME_LOWER}}&uid=$UID" {
+		t.Errorf("redirect = %q", got)
+	}
+}
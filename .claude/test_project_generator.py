@@ -0,0 +1,455 @@
+#!/usr/bin/env python3
+"""
+Tests for the project generator's prebid-adapter options.
+Run: python3 .claude/test_project_generator.py
+"""
+
+import importlib.util
+import json
+import os
+import re
+import shutil
+import subprocess
+import tempfile
+import unittest
+from pathlib import Path
+
+HERE = Path(__file__).resolve().parent
+
+spec = importlib.util.spec_from_file_location("project_generator", HERE / "project-generator.py")
+generator = importlib.util.module_from_spec(spec)
+spec.loader.exec_module(generator)
+generator.get_templates_dir = lambda: HERE / "templates"
+
+
+class PrebidAdapterGeneratorTest(unittest.TestCase):
+    def setUp(self):
+        self.tmp = tempfile.TemporaryDirectory()
+        self.cwd = os.getcwd()
+        os.chdir(self.tmp.name)
+
+    def tearDown(self):
+        os.chdir(self.cwd)
+        self.tmp.cleanup()
+
+    def generate(self, **options) -> Path:
+        self.assertTrue(generator.generate_project("prebid-adapter", "Acme", None, options))
+        return Path(self.tmp.name) / "Acme"
+
+    def test_default_param_alias(self):
+        params = (self.generate() / "params.go").read_text()
+        self.assertIn('"placement_id": "placementId",', params)
+        self.assertIn("func (ext *ExtImpAcme) UnmarshalJSON", params)
+        self.assertNotIn("{{", params)
+
+    def test_custom_param_alias(self):
+        params = (self.generate(param_aliases=["site_id=siteId"]) / "params.go").read_text()
+        self.assertIn('"placement_id": "placementId",\n\t"site_id":      "siteId",', params)
+
+    def test_invalid_param_alias(self):
+        for pair in ["siteId", "zone=zoneId", "siteId=placementId", "site_id=placement_id"]:
+            self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"param_aliases": [pair]}), pair)
+
+    def test_custom_param_alias_schema(self):
+        out = self.generate(param_aliases=["site_id=siteId", "placement=placementId"])
+        schema = json.loads((out / "static" / "bidder-params" / "acme.json").read_text())
+        self.assertEqual(schema["properties"]["site_id"], {"type": "string", "description": "Legacy spelling of siteId"})
+        self.assertEqual(schema["properties"]["placement"]["type"], "string")
+        self.assertEqual(schema["anyOf"], [
+            {"required": ["placementId"]},
+            {"required": ["placement"]},
+            {"required": ["placement_id"]},
+        ])
+        self.assertEqual(generator.check_adapter(out), [])
+
+    def test_no_param_defaults(self):
+        defaults = (self.generate() / "defaults.go").read_text()
+        self.assertIn('const paramDefaults = ""\n', defaults)
+        self.assertIn("if err := decodeParams(bidderExt.Bidder, &impExt); err != nil {", (Path(self.tmp.name) / "Acme" / "adapter.go").read_text())
+
+    def test_param_default_site_id(self):
+        defaults = (self.generate(param_defaults=["siteId=site-1", "minDuration=5"]) / "defaults.go").read_text()
+        self.assertIn('const paramDefaults = "{\\"minDuration\\":5,\\"siteId\\":\\"site-1\\"}"\n', defaults)
+        self.assertNotIn("{{", defaults)
+
+    def test_invalid_param_default(self):
+        for pair in ["siteId", "site=site-1", "placementId=p-1", "minDuration=five"]:
+            self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"param_defaults": [pair]}), pair)
+
+    def test_fuzz_target(self):
+        path = self.generate() / "adapter_fuzz_test.go"
+        fuzz = path.read_text()
+        self.assertTrue(fuzz.startswith("package acme\n"))
+        self.assertIn("func FuzzMakeBids(f *testing.F) {", fuzz)
+        self.assertIn("f.Fuzz(func(t *testing.T, body []byte) {", fuzz)
+        self.assertNotIn("{{", fuzz)
+        self.assert_go_syntax(path)
+
+    def test_schema_parity(self):
+        out = self.generate()
+        schema = json.loads((out / "static" / "bidder-params" / "acme.json").read_text())
+        self.assertEqual(schema["title"], "Acme Adapter Params")
+
+        # Mirror of TestExtImpAcmeSchemaParity for the stub params
+        tags = re.findall(r'`json:"([^",]+)(,omitempty)?"`', (out / "params.go").read_text())
+        self.assertEqual(sorted(schema["properties"]), sorted([name for name, _ in tags] + ["placement_id"]))
+        self.assertEqual([name for name, opt in tags if not opt], ["placementId"])
+        self.assertEqual(schema["anyOf"], [{"required": ["placementId"]}, {"required": ["placement_id"]}])
+        self.assertNotIn("required", schema)
+        self.assertIn("func TestExtImpAcmeSchemaParity(t *testing.T) {", (out / "params_test.go").read_text())
+
+    def assert_go_syntax(self, *paths: Path):
+        # Building needs the PBS module, so settle for a syntax check when gofmt is around
+        if shutil.which("gofmt") is None:
+            self.skipTest("gofmt not installed")
+        result = subprocess.run(["gofmt", "-e", "-l", *map(str, paths)], capture_output=True, text=True)
+        self.assertEqual(result.returncode, 0, result.stderr)
+        self.assertEqual(result.stdout, "")
+
+    def go_files(self, out: Path) -> dict:
+        return {p.relative_to(out): p.read_text() for p in out.rglob("*.go")}
+
+    def test_integration_test(self):
+        out = self.generate()
+        files = self.go_files(out)
+        integration = files[Path("adapter_integration_test.go")]
+        self.assertTrue(integration.startswith("package acme\n"))
+        self.assertIn("func TestIntegrationMockServer(t *testing.T) {", integration)
+        self.assertIn('"net/http/httptest"', integration)
+        self.assertIn("openrtb_ext.BidderAcme,", integration)
+        self.assertIsNone(re.search(r"\{\{[A-Z_]+\}\}", integration))
+        self.assert_go_syntax(out / "adapter_integration_test.go")
+
+    def test_golden_test(self):
+        out = self.generate()
+        golden_test = (out / "adapter_golden_test.go").read_text()
+        self.assertIn("func TestMakeRequestsGolden(t *testing.T) {", golden_test)
+        self.assertIn('flag.Bool("update"', golden_test)
+        self.assertIn('filepath.Join("testdata", "request.golden.json")', golden_test)
+        # The golden file ships pre-recorded so the test passes before any -update run
+        golden = json.loads((out / "testdata" / "request.golden.json").read_text())
+        self.assertEqual(golden["id"], "test-request")
+        self.assertEqual(golden["imp"][0]["ext"], {"bidder": {"placementId": "123"}})
+
+    def test_default_module_path(self):
+        files = self.go_files(self.generate())
+        for path, content in files.items():
+            self.assertNotIn("{{PBS_MODULE}}", content, path)
+        self.assertIn('"github.com/prebid/prebid-server/v2/adapters"', files[Path("adapter.go")])
+
+    def test_custom_module_path(self):
+        files = self.go_files(self.generate(module_path="github.com/acme/pbs-fork/v2/"))
+        self.assertIn('"github.com/acme/pbs-fork/v2/adapters"', files[Path("adapter.go")])
+        self.assertIn('"github.com/acme/pbs-fork/v2/adapters/adapterstest"', files[Path("adapter_json_test.go")])
+        for path, content in files.items():
+            self.assertNotIn("prebid/prebid-server", content, path)
+
+    def test_default_openrtb_version(self):
+        files = self.go_files(self.generate())
+        self.assertIn('"github.com/prebid/openrtb/v20/openrtb2"', files[Path("adapter.go")])
+        for path, content in files.items():
+            self.assertNotIn("{{OPENRTB_MODULE}}", content, path)
+
+    def test_verified_openrtb_version(self):
+        files = self.go_files(self.generate(openrtb_version="20"))
+        self.assertIn('\t"github.com/prebid/openrtb/v20/adcom1"\n\t"github.com/prebid/openrtb/v20/openrtb2"\n', files[Path("adapter.go")])
+        self.assertIn('"github.com/prebid/openrtb/v20/openrtb2"', files[Path("adapter_test.go")])
+
+    def test_unverified_openrtb_version(self):
+        # Only versions the template has been built against are accepted
+        for version in ["19", "21", "v21"]:
+            self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"openrtb_version": version}), version)
+
+    def test_alias_builder_omitted_by_default(self):
+        out = self.generate()
+        self.assertFalse((out / "alias.go").exists())
+        self.assertFalse((out / "alias_test.go").exists())
+
+    def test_alias_builder(self):
+        out = self.generate(alias_builder=True)
+        alias = (out / "alias.go").read_text()
+        self.assertIn("func AliasBuilder(sharedEndpoint string) adapters.Builder {", alias)
+        self.assertIn("func TestAliasBuilder(t *testing.T) {", (out / "alias_test.go").read_text())
+        self.assertNotIn("{{", alias)
+
+    def test_bidder_info_sync_url(self):
+        out = self.generate()
+        info = (out / "static" / "bidder-info" / "acme.yaml").read_text()
+        self.assertIn("https://sync.example.com/acme?gdpr={{.GDPR}}&", info)
+        self.assertFalse((out / "usersync").exists())
+
+    def test_usersync_stub(self):
+        out = self.generate(with_usersync=True)
+        stub = (out / "usersync" / "usersync.go").read_text()
+        self.assertTrue(stub.splitlines()[2].startswith("package acmeusersync"))
+        self.assertIn("{{.GDPRConsent}}", stub)
+        self.assertIn("{{.RedirectURL}}", stub)
+        self.assertIn("func TestBuildSyncURL(t *testing.T) {", (out / "usersync" / "usersync_test.go").read_text())
+        self.assert_go_syntax(out / "usersync" / "usersync.go", out / "usersync" / "usersync_test.go")
+
+        # The stub and bidder-info must agree on the sync URL
+        info = (out / "static" / "bidder-info" / "acme.yaml").read_text()
+        sync_url = re.search(r'const SyncURL = "([^"]+)"', stub).group(1)
+        self.assertIn(f'url: "{sync_url}"', info)
+
+    def test_java_config_omitted_by_default(self):
+        self.assertFalse((self.generate() / "java").exists())
+
+    def test_java_config(self):
+        out = self.generate(with_java=True)
+        resources = out / "java" / "src" / "main" / "resources"
+        java = (resources / "bidder-config" / "acme.yaml").read_text()
+        go = (out / "static" / "bidder-info" / "acme.yaml").read_text()
+        self.assertIn("adapters:\n  acme:\n", java)
+        self.assertIn("cookie-family-name: acme", java)
+
+        # Both servers must be configured alike
+        endpoint = re.search(r'^endpoint: "([^"]+)"', go, re.M).group(1)
+        self.assertIn(f"    endpoint: {endpoint}\n", java)
+        email = re.search(r'email: "([^"]+)"', go).group(1)
+        self.assertIn(f"maintainer-email: {email}\n", java)
+        for platform in ("app", "site"):
+            go_types = re.search(rf"  {platform}:\n    mediaTypes:\n((?:      - \w+\n)+)", go).group(1).split()
+            java_types = re.search(rf"{platform}-media-types:\n((?:        - \w+\n)+)", java).group(1).split()
+            self.assertEqual(go_types, java_types, platform)
+
+        # Java spells the sync macros differently but must hit the same URL
+        macros = {"{{.GDPR}}": "{{gdpr}}", "{{.GDPRConsent}}": "{{gdpr_consent}}", "{{.USPrivacy}}": "{{us_privacy}}",
+                  "{{.GPP}}": "{{gpp}}", "{{.GPPSID}}": "{{gpp_sid}}", "{{.RedirectURL}}": "{{redirect_url}}"}
+        sync_url = re.search(r'url: "([^"]+)"', go).group(1)
+        for go_macro, java_macro in macros.items():
+            sync_url = sync_url.replace(go_macro, java_macro)
+        self.assertIn(f"url: {sync_url}\n", java)
+
+        schema = resources / "static" / "bidder-params" / "acme.json"
+        self.assertEqual(schema.read_text(), (out / "static" / "bidder-params" / "acme.json").read_text())
+
+    def test_replay_omitted_by_default(self):
+        out = self.generate()
+        self.assertFalse((out / "cmd").exists())
+
+    def test_replay_tool(self):
+        out = self.generate(with_replay=True)
+        main = out / "cmd" / "replay" / "main.go"
+        source = main.read_text()
+        self.assertIn("\npackage main\n", source)
+        self.assertIn(f'"{generator.DEFAULT_MODULE_PATH}/adapters/acme"', source)
+        self.assertIn("acme.Builder(", source)
+        self.assertIsNone(re.search(r"\{\{[A-Z_]+\}\}", source))
+        self.assert_go_syntax(main)
+
+    def test_config_omitted_by_default(self):
+        files = self.go_files(self.generate())
+        self.assertNotIn(Path("config.go"), files)
+        self.assertNotIn(Path("config_test.go"), files)
+        self.assertIn("type adapterConfig struct{}", files[Path("config_empty.go")])
+        self.assertFalse(any("yaml" in source for source in files.values()))
+
+    def test_config_fields(self):
+        out = self.generate(config_fields=["host:string", "region_map:map[string]string", "debug:bool"])
+        config = (out / "config.go").read_text()
+        self.assertIn(
+            "type adapterConfig struct {\n"
+            '\tHost      string            `json:"host"`\n'
+            '\tRegionMap map[string]string `json:"region_map"`\n'
+            '\tDebug     bool              `json:"debug"`\n'
+            "}",
+            config,
+        )
+        self.assertFalse((out / "config_empty.go").exists())
+        self.assertIn('Config adapterConfig `json:"config"`', (out / "adapter.go").read_text())
+        self.assert_go_syntax(out / "config.go", out / "config_test.go")
+
+    def test_invalid_config_field(self):
+        for spec in ["host", "host:uint8", "1host:string"]:
+            self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"config_fields": [spec]}), spec)
+
+    def test_json_test_style(self):
+        files = self.go_files(self.generate())
+        self.assertIn('adapterstest.RunJSONBidderTest(t, "acme", bidder)', files[Path("adapter_json_test.go")])
+        self.assertNotIn(Path("adapter_table_test.go"), files)
+
+    def test_table_test_style(self):
+        out = self.generate(test_style="table")
+        files = self.go_files(out)
+        table = files[Path("adapter_table_test.go")]
+        self.assertIn("func TestMakeRequestsTable(t *testing.T) {", table)
+        self.assertIn("func TestMakeBidsTable(t *testing.T) {", table)
+        self.assertNotIn(Path("adapter_json_test.go"), files)
+        for path, content in files.items():
+            self.assertNotIn("adapterstest", content, path)
+        self.assertEqual(generator.check_adapter(out), [])
+
+    def test_invalid_test_style(self):
+        self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"test_style": "bdd"}))
+
+    def test_test_mode_sample_omitted_by_default(self):
+        out = self.generate()
+        self.assertFalse((out / "acme").exists())
+
+    def test_test_mode_sample(self):
+        out = self.generate(test_mode=True)
+        sample = json.loads((out / "acme" / "exemplary" / "test-mode.json").read_text())
+        self.assertEqual(sample["mockBidRequest"]["test"], 1)
+        # The flag must reach the endpoint for it to route to its sandbox
+        self.assertEqual(sample["httpCalls"][0]["expectedRequest"]["body"]["test"], 1)
+        self.assertEqual(sample["httpCalls"][0]["expectedRequest"]["uri"], "https://sandbox.example.com/bid")
+        self.assertIn('"sandboxEndpoint":"https://sandbox.example.com/bid"', (out / "adapter_json_test.go").read_text())
+        self.assertIn('RunJSONBidderTest(t, "acme", bidder)', (out / "adapter_json_test.go").read_text())
+
+    def test_test_mode_needs_json_style(self):
+        self.assertFalse(generator.generate_project("prebid-adapter", "Acme", None, {"test_mode": True, "test_style": "table"}))
+
+    def test_no_license_header_by_default(self):
+        out = self.generate()
+        self.assertTrue((out / "adapter.go").read_text().startswith("package acme\n"))
+
+    def test_license_header(self):
+        out = self.generate(license="Copyright 2026 Example Corp\n\nLicensed under the Apache License, Version 2.0\n", with_usersync=True)
+        header = "// Copyright 2026 Example Corp\n//\n// Licensed under the Apache License, Version 2.0\n\n"
+        files = self.go_files(out)
+        for path, source in files.items():
+            self.assertTrue(source.startswith(header), path)
+            # The blank line keeps the header from becoming the package doc
+            rest = source[len(header):]
+            self.assertTrue(re.match(r"(//.*\n)*package \w+\n", rest), path)
+
+        stub = files[Path("usersync/usersync.go")]
+        self.assertIn(header + "// Package acmeusersync", stub)
+
+        if shutil.which("gofmt") is None:
+            self.skipTest("gofmt not installed")
+        result = subprocess.run(["gofmt", "-l", *(str(out / path) for path in files)], capture_output=True, text=True)
+        self.assertEqual(result.returncode, 0, result.stderr)
+        self.assertEqual(result.stdout, "")
+
+    def test_license_header_keeps_comment_lines(self):
+        self.assertEqual(generator.license_header("// SPDX-License-Identifier: MIT"), "// SPDX-License-Identifier: MIT\n\n")
+        self.assertEqual(generator.license_header("  \n"), "")
+
+    def test_helpers_omitted_by_default(self):
+        out = self.generate()
+        self.assertFalse((out / "helpers.go").exists())
+        self.assertFalse((Path(self.tmp.name) / "lughhelpers").exists())
+        self.assertIn("func convertFloor(", (out / "helpers_local.go").read_text())
+
+    def test_shared_helpers(self):
+        out = self.generate(with_helpers=True)
+        helpers_dir = Path(self.tmp.name) / "lughhelpers"
+        helpers = (helpers_dir / "helpers.go").read_text()
+        self.assertIn("\npackage lughhelpers\n", helpers)
+        self.assertIn("func ConvertFloor(", helpers)
+        self.assertIn("func TestConvertFloor(t *testing.T) {", (helpers_dir / "helpers_test.go").read_text())
+
+        # The adapter must import the package at the path its copy instructions give
+        adapter_helpers = (out / "helpers.go").read_text()
+        self.assertIn(f'"{generator.DEFAULT_MODULE_PATH}/adapters/internal/lughhelpers"', adapter_helpers)
+        self.assertIn("lughhelpers.ConvertFloor(", adapter_helpers)
+        self.assertIn("lughhelpers.BidMeta(", adapter_helpers)
+        self.assertFalse((out / "helpers_local.go").exists())
+
+        sources = [*helpers_dir.glob("*.go"), out / "helpers.go"]
+        for source in sources:
+            self.assertIsNone(re.search(r"\{\{[A-Z_]+\}\}", source.read_text()), source.name)
+        self.assert_go_syntax(*sources)
+
+    def test_local_helpers_match_shared(self):
+        # adapter.go calls the same functions whichever file provides them
+        shared = (generator.get_templates_dir() / "prebid-adapter" / "helpers.go").read_text()
+        local = (generator.get_templates_dir() / "prebid-adapter" / "helpers_local.go").read_text()
+        signature = r"^(?:type rateCache\b|func \w+\(.*)"
+        self.assertEqual(re.findall(signature, shared, re.M), re.findall(signature, local, re.M))
+
+    def test_shared_helpers_emitted_once(self):
+        self.generate(with_helpers=True)
+        helpers = Path(self.tmp.name) / "lughhelpers" / "helpers.go"
+        helpers.write_text(helpers.read_text() + "// local change\n")
+        self.assertTrue(generator.generate_project("prebid-adapter", "Other", None, {"with_helpers": True}))
+        self.assertTrue(helpers.read_text().endswith("// local change\n"))
+        self.assertTrue((Path(self.tmp.name) / "Other" / "helpers.go").exists())
+
+    def test_shared_dir_is_not_a_template(self):
+        self.assertNotIn("_shared", generator.list_templates())
+        self.assertFalse(generator.generate_project("_shared", "Acme", None, {}))
+
+    def test_check_conforming_adapter(self):
+        self.assertEqual(generator.check_adapter(self.generate()), [])
+
+    def test_check_pbs_layout(self):
+        # Install the adapter the way the generator's next steps describe
+        out = self.generate()
+        root = Path(self.tmp.name) / "prebid-server"
+        adapter_dir = root / "adapters" / "acme"
+        shutil.copytree(out, adapter_dir, ignore=shutil.ignore_patterns("params.go", "params_test.go", "static"))
+        (root / "openrtb_ext").mkdir()
+        shutil.copy2(out / "params.go", root / "openrtb_ext" / "imp_acme.go")
+        shutil.copy2(out / "params_test.go", root / "openrtb_ext" / "imp_acme_test.go")
+        shutil.copytree(out / "static", root / "static")
+
+        self.assertEqual(generator.check_adapter(adapter_dir), [])
+
+        (root / "openrtb_ext" / "imp_acme.go").unlink()
+        self.assertEqual(generator.check_adapter(adapter_dir), [
+            "missing ExtImp params struct",
+        ])
+
+    def test_check_drifted_adapter(self):
+        out = self.generate()
+        adapter = out / "adapter.go"
+        adapter.write_text(adapter.read_text().replace("func (a *adapter) MakeBids(", "func (a *adapter) makeBids("))
+        schema_path = out / "static" / "bidder-params" / "acme.json"
+        schema = json.loads(schema_path.read_text())
+        schema["properties"]["zoneId"] = {"type": "string"}
+        del schema["properties"]["placement_id"]
+        del schema["anyOf"]
+        schema_path.write_text(json.dumps(schema))
+
+        self.assertEqual(generator.check_adapter(out), [
+            "missing func MakeBids",
+            "schema property zoneId has no params field",
+            "param alias placement_id is not in the schema",
+            "params field placementId required=True but schema required=False",
+        ])
+
+    def test_check_optional_funcs(self):
+        # An adapter generated before the template grew DecoderBuilder has not drifted
+        out = self.generate()
+        adapter = out / "adapter.go"
+        source = adapter.read_text()
+        start = source.index("// DecoderBuilder returns")
+        end = source.index("\n}\n", start) + 3
+        adapter.write_text(source[:start] + source[end:])
+
+        self.assertEqual(generator.check_adapter(out), [])
+
+    def test_check_signature_mismatch(self):
+        out = self.generate()
+        adapter = out / "adapter.go"
+        adapter.write_text(adapter.read_text().replace(
+            "func Builder(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {",
+            "func Builder(bidderName openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {",
+        ))
+
+        self.assertEqual(generator.check_adapter(out), [
+            "func Builder has signature (openrtb_ext.BidderName, config.Adapter) (adapters.Bidder, error), "
+            "expected (openrtb_ext.BidderName, config.Adapter, config.Server) (adapters.Bidder, error)",
+        ])
+
+    def test_check_alias_not_required_alternative(self):
+        out = self.generate()
+        schema_path = out / "static" / "bidder-params" / "acme.json"
+        schema = json.loads(schema_path.read_text())
+        del schema["anyOf"]
+        schema["required"] = ["placementId"]
+        schema_path.write_text(json.dumps(schema))
+
+        self.assertEqual(generator.check_adapter(out), [
+            "params field placementId is required as any of ['placementId'], expected ['placementId', 'placement_id']",
+        ])
+
+    def test_check_missing_dir(self):
+        self.assertFalse(generator.check_command(str(Path(self.tmp.name) / "missing")))
+
+
+if __name__ == "__main__":
+    unittest.main()